@@ -0,0 +1,75 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package exemplar
+
+import "math/rand"
+
+// Policy decides which exemplars an aggregation retains for a bucket, or
+// for the single value of a sum or last value aggregation.
+//
+// Offer is called for every sample with the exemplars retained so far, the
+// exemplar of the sample and the number of samples seen, including e. It
+// returns the exemplars to retain. Offer must not modify held: return a new
+// slice to change the retained exemplars.
+type Policy interface {
+	Offer(held []*Exemplar, e *Exemplar, n int64) []*Exemplar
+}
+
+// PolicyFunc adapts a function to a Policy.
+type PolicyFunc func(held []*Exemplar, e *Exemplar, n int64) []*Exemplar
+
+// Offer calls f.
+func (f PolicyFunc) Offer(held []*Exemplar, e *Exemplar, n int64) []*Exemplar {
+	return f(held, e, n)
+}
+
+// Latest returns a Policy that retains the exemplar of the latest sample.
+func Latest() Policy {
+	return PolicyFunc(func(held []*Exemplar, e *Exemplar, n int64) []*Exemplar {
+		return []*Exemplar{e}
+	})
+}
+
+// TraceSampled returns a Policy that retains the exemplar of the latest
+// sample recorded in a sampled trace, that is with a KeyTraceID attachment.
+func TraceSampled() Policy {
+	return PolicyFunc(func(held []*Exemplar, e *Exemplar, n int64) []*Exemplar {
+		if _, ok := e.Attachments[KeyTraceID]; !ok {
+			return held
+		}
+		return []*Exemplar{e}
+	})
+}
+
+// Reservoir returns a Policy that retains a uniform random sample of at
+// most n exemplars of all the samples.
+func Reservoir(n int) Policy {
+	return PolicyFunc(func(held []*Exemplar, e *Exemplar, seen int64) []*Exemplar {
+		if n <= 0 {
+			return held
+		}
+		if len(held) < n {
+			return append(held[:len(held):len(held)], e)
+		}
+		i := rand.Int63n(seen)
+		if i >= int64(n) {
+			return held
+		}
+		h := append([]*Exemplar(nil), held...)
+		h[i] = e
+		return h
+	})
+}
@@ -22,9 +22,11 @@ import (
 	"log"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
 
 	"go.opencensus.io/internal"
+	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 
@@ -48,6 +50,11 @@ type Options struct {
 	Registry    *prometheus.Registry
 	OnError     func(err error)
 	ConstLabels prometheus.Labels // ConstLabels will be set as labels on all views.
+
+	// UnitSuffix appends the unit of the views to the metric names, such
+	// as "_seconds" for views in "s" or "_bytes_per_second" for views in
+	// "By/s". Names already ending with the suffix are kept.
+	UnitSuffix bool
 }
 
 // NewExporter returns an exporter that exports stats to Prometheus.
@@ -71,14 +78,14 @@ var _ view.Exporter = (*Exporter)(nil)
 func (c *collector) registerViews(views ...*view.View) {
 	count := 0
 	for _, view := range views {
-		sig := viewSignature(c.opts.Namespace, view)
+		sig := viewSignature(c.opts, view)
 		c.registeredViewsMu.Lock()
 		_, ok := c.registeredViews[sig]
 		c.registeredViewsMu.Unlock()
 
 		if !ok {
 			desc := prometheus.NewDesc(
-				viewName(c.opts.Namespace, view),
+				viewName(c.opts, view),
 				view.Description,
 				tagKeysToLabels(view.TagKeys),
 				c.opts.ConstLabels,
@@ -123,7 +130,8 @@ func (o *Options) onError(err error) {
 // Each OpenCensus AggregationData will be converted to
 // corresponding Prometheus Metric: SumData will be converted
 // to Untyped Metric, CountData will be a Counter Metric,
-// DistributionData will be a Histogram Metric.
+// DistributionData and ExponentialData will be a Histogram Metric, QuantileData
+// will be a Summary Metric.
 func (e *Exporter) ExportView(vd *view.Data) {
 	if len(vd.Rows) == 0 {
 		return
@@ -159,7 +167,7 @@ type collector struct {
 
 func (c *collector) addViewData(vd *view.Data) {
 	c.registerViews(vd.View)
-	sig := viewSignature(c.opts.Namespace, vd.View)
+	sig := viewSignature(c.opts, vd.View)
 
 	c.mu.Lock()
 	c.viewData[sig] = vd
@@ -188,7 +196,7 @@ func (c *collector) Collect(ch chan<- prometheus.Metric) {
 	viewData := c.cloneViewData()
 
 	for _, vd := range viewData {
-		sig := viewSignature(c.opts.Namespace, vd.View)
+		sig := viewSignature(c.opts, vd.View)
 		c.registeredViewsMu.Lock()
 		desc := c.registeredViews[sig]
 		c.registeredViewsMu.Unlock()
@@ -237,12 +245,29 @@ func (c *collector) toMetric(desc *prometheus.Desc, v *view.View, row *view.Row)
 		}
 		return prometheus.NewConstHistogram(desc, uint64(data.Count), data.Sum(), points, tagValues(row.Tags, v.TagKeys)...)
 
+	case *view.ExponentialData:
+		dd := data.DistributionData()
+		points := make(map[float64]uint64)
+		cumCount := uint64(0)
+		for i, b := range data.Bounds() {
+			cumCount += uint64(dd.CountPerBucket[i])
+			points[b] = cumCount
+		}
+		return prometheus.NewConstHistogram(desc, uint64(data.Count), data.Sum(), points, tagValues(row.Tags, v.TagKeys)...)
+
 	case *view.SumData:
 		return prometheus.NewConstMetric(desc, prometheus.UntypedValue, data.Value, tagValues(row.Tags, v.TagKeys)...)
 
 	case *view.LastValueData:
 		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, data.Value, tagValues(row.Tags, v.TagKeys)...)
 
+	case *view.QuantileData:
+		quantiles := make(map[float64]float64, len(data.Quantiles))
+		for i, q := range data.Quantiles {
+			quantiles[q] = data.Values[i]
+		}
+		return prometheus.NewConstSummary(desc, uint64(data.Count), data.Sum, quantiles, tagValues(row.Tags, v.TagKeys)...)
+
 	default:
 		return nil, fmt.Errorf("aggregation %T is not yet supported", v.Aggregation)
 	}
@@ -283,17 +308,79 @@ func tagValues(t []tag.Tag, expectedKeys []tag.Key) []string {
 	return values
 }
 
-func viewName(namespace string, v *view.View) string {
+func viewName(o Options, v *view.View) string {
 	var name string
-	if namespace != "" {
-		name = namespace + "_"
+	if o.Namespace != "" {
+		name = o.Namespace + "_"
+	}
+	name += internal.Sanitize(v.Name)
+	if o.UnitSuffix {
+		if suffix := unitSuffix(v.Unit); suffix != "" && !strings.HasSuffix(name, "_"+suffix) {
+			name += "_" + suffix
+		}
+	}
+	return name
+}
+
+// unitNames holds the singular and plural names of the unit atoms.
+var unitNames = map[string][2]string{
+	"s":   {"second", "seconds"},
+	"min": {"minute", "minutes"},
+	"h":   {"hour", "hours"},
+	"d":   {"day", "days"},
+	"By":  {"byte", "bytes"},
+	"bit": {"bit", "bits"},
+	"m":   {"meter", "meters"},
+	"g":   {"gram", "grams"},
+	"Hz":  {"hertz", "hertz"},
+	"V":   {"volt", "volts"},
+	"A":   {"ampere", "amperes"},
+	"J":   {"joule", "joules"},
+	"W":   {"watt", "watts"},
+	"K":   {"kelvin", "kelvins"},
+	"%":   {"percent", "percent"},
+}
+
+var unitPrefixNames = map[string]string{
+	"Y": "yotta", "Z": "zetta", "E": "exa", "P": "peta", "T": "tera", "G": "giga",
+	"M": "mega", "k": "kilo", "h": "hecto", "da": "deca", "d": "deci", "c": "centi",
+	"m": "milli", "u": "micro", "n": "nano", "p": "pico", "f": "femto", "a": "atto",
+	"z": "zepto", "y": "yocto",
+	"Ki": "kibi", "Mi": "mebi", "Gi": "gibi", "Ti": "tebi", "Pi": "pebi", "Ei": "exbi",
+}
+
+// unitSuffix returns the Prometheus metric name suffix of the unit, or an
+// empty string for dimensionless or unknown units.
+func unitSuffix(unit string) string {
+	u, err := stats.ParseUnit(unit)
+	if err != nil {
+		return ""
+	}
+	suffix := unitName(u, true)
+	if suffix == "" || u.Per == nil {
+		return suffix
+	}
+	if per := unitName(*u.Per, false); per != "" {
+		suffix += "_per_" + per
+	}
+	return suffix
+}
+
+func unitName(u stats.Unit, plural bool) string {
+	names, ok := unitNames[u.Atom]
+	if !ok {
+		return ""
+	}
+	name := names[0]
+	if plural {
+		name = names[1]
 	}
-	return name + internal.Sanitize(v.Name)
+	return unitPrefixNames[u.Prefix] + name
 }
 
-func viewSignature(namespace string, v *view.View) string {
+func viewSignature(o Options, v *view.View) string {
 	var buf bytes.Buffer
-	buf.WriteString(viewName(namespace, v))
+	buf.WriteString(viewName(o, v))
 	for _, k := range v.TagKeys {
 		buf.WriteString("-" + k.Name())
 	}
@@ -504,3 +504,78 @@ tests_foo{key_1="issue659",key_2="",key_3="issue659",key_4="",key_5="issue659"}
 		t.Fatalf("output differed from expected output: %s want: %s", output, want)
 	}
 }
+
+func TestQuantilesAsSummary(t *testing.T) {
+	exporter, err := NewExporter(Options{})
+	if err != nil {
+		t.Fatalf("failed to create prometheus exporter: %v", err)
+	}
+	exporter.ExportView(&view.Data{
+		View: newView("TestQuantilesAsSummary/m1", view.Quantiles(0.5, 0.99)),
+		Rows: []*view.Row{
+			{Data: &view.QuantileData{
+				Count:     4,
+				Sum:       10,
+				Quantiles: []float64{0.5, 0.99},
+				Values:    []float64{2, 4},
+			}},
+		},
+	})
+
+	cst := httptest.NewServer(exporter)
+	defer cst.Close()
+	res, err := http.Get(cst.URL)
+	if err != nil {
+		t.Fatalf("http.Get error: %v", err)
+	}
+	blob, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Read body error: %v", err)
+	}
+	for _, want := range []string{
+		"# TYPE foo summary",
+		`foo{quantile="0.5"} 2`,
+		`foo{quantile="0.99"} 4`,
+		"foo_sum 10",
+		"foo_count 4",
+	} {
+		if !strings.Contains(string(blob), want) {
+			t.Errorf("output does not contain %q:\n%s", want, blob)
+		}
+	}
+}
+
+func TestUnitSuffix(t *testing.T) {
+	tests := []struct {
+		unit string
+		want string
+	}{
+		{"s", "seconds"},
+		{"ms", "milliseconds"},
+		{"KiBy", "kibibytes"},
+		{"By/s", "bytes_per_second"},
+		{"%", "percent"},
+		{"1", ""},
+		{"{request}", ""},
+		{"unknown", ""},
+	}
+	for _, tt := range tests {
+		if got := unitSuffix(tt.unit); got != tt.want {
+			t.Errorf("unitSuffix(%q) = %q; want %q", tt.unit, got, tt.want)
+		}
+	}
+
+	o := Options{Namespace: "ns", UnitSuffix: true}
+	for _, tt := range []struct {
+		name, unit, want string
+	}{
+		{"latency", "s", "ns_latency_seconds"},
+		{"latency_seconds", "s", "ns_latency_seconds"},
+		{"requests", "1", "ns_requests"},
+	} {
+		v := &view.View{Name: tt.name, Unit: tt.unit}
+		if got := viewName(o, v); got != tt.want {
+			t.Errorf("viewName(%q in %q) = %q; want %q", tt.name, tt.unit, got, tt.want)
+		}
+	}
+}
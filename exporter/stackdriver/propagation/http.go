@@ -33,15 +33,28 @@ const (
 	httpHeader        = `X-Cloud-Trace-Context`
 )
 
-var _ propagation.HTTPFormat = (*HTTPFormat)(nil)
+var (
+	_ propagation.HTTPFormat    = (*HTTPFormat)(nil)
+	_ propagation.TextMapFormat = (*HTTPFormat)(nil)
+)
 
 // HTTPFormat implements propagation.HTTPFormat to propagate
 // traces in HTTP headers for Google Cloud Platform and Stackdriver Trace.
+// It also implements propagation.TextMapFormat to propagate traces in
+// any other carrier.
 type HTTPFormat struct{}
 
 // SpanContextFromRequest extracts a Stackdriver Trace span context from incoming requests.
 func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (sc trace.SpanContext, ok bool) {
-	h := req.Header.Get(httpHeader)
+	return f.SpanContextFromCarrier(propagation.HTTPHeaderCarrier(req.Header))
+}
+
+// SpanContextFromCarrier extracts a Stackdriver Trace span context from the carrier.
+func (f *HTTPFormat) SpanContextFromCarrier(c propagation.TextMapCarrier) (sc trace.SpanContext, ok bool) {
+	var h string
+	if v := c.Get(httpHeader); len(v) > 0 {
+		h = v[0]
+	}
 	// See https://cloud.google.com/trace/docs/faq for the header HTTPFormat.
 	// Return if the header is empty or missing, or if the header is unreasonably
 	// large, to avoid making unnecessary copies of a large string.
@@ -88,7 +101,12 @@ func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (sc trace.SpanCon
 
 // SpanContextToRequest modifies the given request to include a Stackdriver Trace header.
 func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
+	f.SpanContextToCarrier(sc, propagation.HTTPHeaderCarrier(req.Header))
+}
+
+// SpanContextToCarrier modifies the given carrier to include a Stackdriver Trace header.
+func (f *HTTPFormat) SpanContextToCarrier(sc trace.SpanContext, c propagation.TextMapCarrier) {
 	sid := binary.BigEndian.Uint64(sc.SpanID[:])
 	header := fmt.Sprintf("%s/%d;o=%d", hex.EncodeToString(sc.TraceID[:]), sid, int64(sc.TraceOptions))
-	req.Header.Set(httpHeader, header)
+	c.Set(httpHeader, header)
 }
@@ -16,6 +16,7 @@ package metric_test
 
 import (
 	"net/http"
+	"time"
 
 	"go.opencensus.io/metric"
 	"go.opencensus.io/metric/metricdata"
@@ -34,3 +35,19 @@ func ExampleRegistry_AddInt64Gauge() {
 		// process request ...
 	})
 }
+
+func ExampleRegistry_AddHistogram() {
+	r := metric.NewRegistry()
+
+	h := r.AddHistogram("request_latency", "Latency of requests, per method.", metricdata.UnitMilliseconds,
+		metric.ExponentialBounds(1, 2, 12), "method")
+
+	http.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		defer func() {
+			ms := float64(time.Since(start)) / float64(time.Millisecond)
+			h.GetEntry(metricdata.NewLabelValue(request.Method)).Record(ms)
+		}()
+		// process request ...
+	})
+}
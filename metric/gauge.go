@@ -53,8 +53,12 @@ func (g *gauge) read() *metricdata.Metric {
 		entry := v.(gaugeEntry)
 		key := k.(string)
 		labelVals := g.labelValues(key)
+		start := now // Gauge value is instantaneous.
+		if g.desc.Type == metricdata.TypeCumulativeDistribution {
+			start = g.start
+		}
 		m.TimeSeries = append(m.TimeSeries, &metricdata.TimeSeries{
-			StartTime:   now, // Gauge value is instantaneous.
+			StartTime:   start,
 			LabelValues: labelVals,
 			Points: []metricdata.Point{
 				entry.read(now),
@@ -36,6 +36,7 @@ func TestGauge(t *testing.T) {
 		{
 			Descriptor: metricdata.Descriptor{
 				Name:      "TestGauge",
+				Type:      metricdata.TypeGaugeFloat64,
 				LabelKeys: []string{"k1", "k2"},
 			},
 			TimeSeries: []*metricdata.TimeSeries{
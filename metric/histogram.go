@@ -0,0 +1,103 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metric
+
+import (
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+)
+
+// Histogram represents the distribution of recorded values, such as request
+// latencies, in buckets with fixed bounds.
+//
+// Histogram maintains a distribution for each combination of label values
+// passed to the GetEntry method.
+type Histogram struct {
+	g      gauge
+	bounds []float64
+}
+
+// HistogramEntry represents the distribution of the values recorded for a set
+// of label values.
+type HistogramEntry struct {
+	mu     sync.Mutex
+	bounds []float64
+	count  int64
+	mean   float64
+	ssd    float64 // sum of the squared deviations from the mean
+	counts []int64
+}
+
+// ExponentialBounds returns n bucket bounds growing by factor from start,
+// that is start, start*factor, start*factor^2 and so on.
+func ExponentialBounds(start, factor float64, n int) []float64 {
+	if start <= 0 || factor <= 1 || n < 1 {
+		log.Panicf("invalid exponential bounds: start %v, factor %v, n %d", start, factor, n)
+	}
+	bounds := make([]float64, n)
+	for i := range bounds {
+		bounds[i] = start * math.Pow(factor, float64(i))
+	}
+	return bounds
+}
+
+// GetEntry returns a histogram entry where each key for this histogram has
+// the value given.
+//
+// The number of label values supplied must be exactly the same as the number
+// of keys supplied when this histogram was created.
+func (h *Histogram) GetEntry(labelVals ...metricdata.LabelValue) *HistogramEntry {
+	return h.g.entryForValues(labelVals, func() gaugeEntry {
+		return &HistogramEntry{
+			bounds: h.bounds,
+			counts: make([]int64, len(h.bounds)+1),
+		}
+	}).(*HistogramEntry)
+}
+
+// Record adds val to the distribution of the histogram entry.
+func (e *HistogramEntry) Record(val float64) {
+	i := sort.Search(len(e.bounds), func(i int) bool { return val < e.bounds[i] })
+	e.mu.Lock()
+	e.counts[i]++
+	e.count++
+	// Welford's method, see metricdata.Distribution.SumOfSquaredDeviation.
+	delta := val - e.mean
+	e.mean += delta / float64(e.count)
+	e.ssd += delta * (val - e.mean)
+	e.mu.Unlock()
+}
+
+func (e *HistogramEntry) read(t time.Time) metricdata.Point {
+	e.mu.Lock()
+	d := &metricdata.Distribution{
+		Count:                 e.count,
+		Sum:                   e.mean * float64(e.count),
+		SumOfSquaredDeviation: e.ssd,
+		BucketOptions:         &metricdata.BucketOptions{Bounds: e.bounds},
+		Buckets:               make([]metricdata.Bucket, len(e.counts)),
+	}
+	for i, c := range e.counts {
+		d.Buckets[i].Count = c
+	}
+	e.mu.Unlock()
+	return metricdata.NewDistributionPoint(t, d)
+}
@@ -0,0 +1,107 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metric
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/metric/metricdata"
+)
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.AddHistogram("TestHistogram", "", metricdata.UnitMilliseconds, []float64{2, 4}, "k1")
+	for _, v := range []float64{1, 2, 3, 6} {
+		h.GetEntry(metricdata.NewLabelValue("v1")).Record(v)
+	}
+	h.GetEntry(metricdata.LabelValue{}).Record(10)
+	m := r.ReadAll()
+	want := []*metricdata.Metric{
+		{
+			Descriptor: metricdata.Descriptor{
+				Name:      "TestHistogram",
+				Unit:      metricdata.UnitMilliseconds,
+				Type:      metricdata.TypeCumulativeDistribution,
+				LabelKeys: []string{"k1"},
+			},
+			TimeSeries: []*metricdata.TimeSeries{
+				{
+					LabelValues: []metricdata.LabelValue{{}},
+					Points: []metricdata.Point{
+						metricdata.NewDistributionPoint(time.Time{}, &metricdata.Distribution{
+							Count:         1,
+							Sum:           10,
+							BucketOptions: &metricdata.BucketOptions{Bounds: []float64{2, 4}},
+							Buckets:       []metricdata.Bucket{{}, {}, {Count: 1}},
+						}),
+					},
+				},
+				{
+					LabelValues: []metricdata.LabelValue{metricdata.NewLabelValue("v1")},
+					Points: []metricdata.Point{
+						metricdata.NewDistributionPoint(time.Time{}, &metricdata.Distribution{
+							Count:                 4,
+							Sum:                   12,
+							SumOfSquaredDeviation: 14,
+							BucketOptions:         &metricdata.BucketOptions{Bounds: []float64{2, 4}},
+							Buckets:               []metricdata.Bucket{{Count: 1}, {Count: 2}, {Count: 1}},
+						}),
+					},
+				},
+			},
+		},
+	}
+	canonicalize(m)
+	canonicalize(want)
+	if diff := cmp.Diff(m, want, cmp.Comparer(ignoreTimes)); diff != "" {
+		t.Errorf("-got +want: %s", diff)
+	}
+	if start := m[0].TimeSeries[0].StartTime; start.IsZero() || start.After(m[0].TimeSeries[0].Points[0].Time) {
+		t.Errorf("start time = %v; want the time the histogram was created", start)
+	}
+}
+
+func TestExponentialBounds(t *testing.T) {
+	got := ExponentialBounds(1, 2, 4)
+	want := []float64{1, 2, 4, 8}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("-got +want: %s", diff)
+	}
+}
+
+func TestHistogramEntry_SumOfSquaredDeviation(t *testing.T) {
+	r := NewRegistry()
+	h := r.AddHistogram("h", "", metricdata.UnitDimensionless, ExponentialBounds(1, 10, 3))
+	e := h.GetEntry()
+	var sum float64
+	values := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+	for _, v := range values {
+		e.Record(v)
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var want float64
+	for _, v := range values {
+		want += (v - mean) * (v - mean)
+	}
+	d := r.ReadAll()[0].TimeSeries[0].Points[0].Value.(*metricdata.Distribution)
+	if math.Abs(d.SumOfSquaredDeviation-want) > 1e-6 {
+		t.Errorf("SumOfSquaredDeviation = %v; want %v", d.SumOfSquaredDeviation, want)
+	}
+}
@@ -14,6 +14,9 @@
 
 // Package metricdata contains the metrics data model.
 //
+// Metrics can be encoded as JSON with encoding/json, and checked against the
+// invariants of the model with Metric.Validate.
+//
 // This is an EXPERIMENTAL package, and may change in arbitrary ways without
 // notice.
 package metricdata // import "go.opencensus.io/metric/metricdata"
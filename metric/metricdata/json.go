@@ -0,0 +1,432 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metricdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opencensus.io/exemplar"
+	"go.opencensus.io/resource"
+)
+
+// The JSON encoding of metrics uses the lower case, underscore separated
+// names of the fields. Label values that are not present are encoded as null,
+// metric types by their name, such as "TypeCumulativeInt64", and float64
+// values that are not finite as the strings "NaN", "+Inf" and "-Inf".
+
+type jsonMetric struct {
+	Descriptor jsonDescriptor `json:"descriptor"`
+	Resource   *jsonResource  `json:"resource,omitempty"`
+	TimeSeries []*TimeSeries  `json:"time_series"`
+}
+
+type jsonResource struct {
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type jsonDescriptor struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Unit        Unit     `json:"unit,omitempty"`
+	Type        string   `json:"type"`
+	LabelKeys   []string `json:"label_keys,omitempty"`
+}
+
+// MarshalJSON encodes m as JSON.
+func (m *Metric) MarshalJSON() ([]byte, error) {
+	jm := jsonMetric{
+		Descriptor: jsonDescriptor{
+			Name:        m.Descriptor.Name,
+			Description: m.Descriptor.Description,
+			Unit:        m.Descriptor.Unit,
+			Type:        m.Descriptor.Type.String(),
+			LabelKeys:   m.Descriptor.LabelKeys,
+		},
+		TimeSeries: m.TimeSeries,
+	}
+	if m.Resource != nil {
+		jm.Resource = &jsonResource{Type: m.Resource.Type, Labels: m.Resource.Labels}
+	}
+	return json.Marshal(jm)
+}
+
+// UnmarshalJSON decodes m from JSON encoded by MarshalJSON.
+func (m *Metric) UnmarshalJSON(b []byte) error {
+	var jm jsonMetric
+	if err := json.Unmarshal(b, &jm); err != nil {
+		return err
+	}
+	t, err := parseType(jm.Descriptor.Type)
+	if err != nil {
+		return err
+	}
+	*m = Metric{
+		Descriptor: Descriptor{
+			Name:        jm.Descriptor.Name,
+			Description: jm.Descriptor.Description,
+			Unit:        jm.Descriptor.Unit,
+			Type:        t,
+			LabelKeys:   jm.Descriptor.LabelKeys,
+		},
+		TimeSeries: jm.TimeSeries,
+	}
+	if jm.Resource != nil {
+		m.Resource = &resource.Resource{Type: jm.Resource.Type, Labels: jm.Resource.Labels}
+	}
+	return nil
+}
+
+func parseType(s string) (Type, error) {
+	for t := TypeGaugeInt64; t <= TypeSummary; t++ {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("metricdata: unknown metric type %q", s)
+}
+
+type jsonTimeSeries struct {
+	LabelValues []*string `json:"label_values,omitempty"`
+	Points      []Point   `json:"points"`
+	StartTime   time.Time `json:"start_time"`
+}
+
+// MarshalJSON encodes ts as JSON.
+func (ts *TimeSeries) MarshalJSON() ([]byte, error) {
+	jts := jsonTimeSeries{
+		Points:    ts.Points,
+		StartTime: ts.StartTime,
+	}
+	for _, v := range ts.LabelValues {
+		var s *string
+		if v.Present {
+			value := v.Value
+			s = &value
+		}
+		jts.LabelValues = append(jts.LabelValues, s)
+	}
+	return json.Marshal(jts)
+}
+
+// UnmarshalJSON decodes ts from JSON encoded by MarshalJSON.
+func (ts *TimeSeries) UnmarshalJSON(b []byte) error {
+	var jts jsonTimeSeries
+	if err := json.Unmarshal(b, &jts); err != nil {
+		return err
+	}
+	*ts = TimeSeries{
+		Points:    jts.Points,
+		StartTime: jts.StartTime,
+	}
+	for _, s := range jts.LabelValues {
+		var v LabelValue
+		if s != nil {
+			v = NewLabelValue(*s)
+		}
+		ts.LabelValues = append(ts.LabelValues, v)
+	}
+	return nil
+}
+
+type jsonPoint struct {
+	Time         time.Time       `json:"time"`
+	Int64        *int64          `json:"int64,omitempty"`
+	Float64      *jsonFloat      `json:"float64,omitempty"`
+	Distribution *Distribution   `json:"distribution,omitempty"`
+	Summary      *Summary        `json:"summary,omitempty"`
+	Exemplars    []*jsonExemplar `json:"exemplars,omitempty"`
+}
+
+// jsonPointVisitor sets the value of a jsonPoint.
+type jsonPointVisitor struct {
+	p *jsonPoint
+}
+
+func (v jsonPointVisitor) VisitInt64Value(i int64)                { v.p.Int64 = &i }
+func (v jsonPointVisitor) VisitFloat64Value(f float64)            { jf := jsonFloat(f); v.p.Float64 = &jf }
+func (v jsonPointVisitor) VisitDistributionValue(d *Distribution) { v.p.Distribution = d }
+func (v jsonPointVisitor) VisitSummaryValue(s *Summary)           { v.p.Summary = s }
+
+// MarshalJSON encodes p as JSON. The value is encoded in the field named
+// after its kind: int64, float64, distribution or summary.
+func (p Point) MarshalJSON() ([]byte, error) {
+	jp := jsonPoint{
+		Time:      p.Time,
+		Exemplars: toJSONExemplars(p.Exemplars),
+	}
+	p.ReadValue(jsonPointVisitor{&jp})
+	return json.Marshal(jp)
+}
+
+// UnmarshalJSON decodes p from JSON encoded by MarshalJSON.
+func (p *Point) UnmarshalJSON(b []byte) error {
+	var jp jsonPoint
+	if err := json.Unmarshal(b, &jp); err != nil {
+		return err
+	}
+	*p = Point{
+		Time:      jp.Time,
+		Exemplars: fromJSONExemplars(jp.Exemplars),
+	}
+	n := 0
+	if jp.Int64 != nil {
+		p.Value = *jp.Int64
+		n++
+	}
+	if jp.Float64 != nil {
+		p.Value = float64(*jp.Float64)
+		n++
+	}
+	if jp.Distribution != nil {
+		p.Value = jp.Distribution
+		n++
+	}
+	if jp.Summary != nil {
+		p.Value = jp.Summary
+		n++
+	}
+	if n != 1 {
+		return errors.New("metricdata: point must have exactly one value")
+	}
+	return nil
+}
+
+type jsonDistribution struct {
+	Count                 int64          `json:"count"`
+	Sum                   jsonFloat      `json:"sum"`
+	SumOfSquaredDeviation jsonFloat      `json:"sum_of_squared_deviation"`
+	BucketOptions         *BucketOptions `json:"bucket_options,omitempty"`
+	Buckets               []jsonBucket   `json:"buckets,omitempty"`
+}
+
+type jsonBucket struct {
+	Count     int64           `json:"count"`
+	Exemplar  *jsonExemplar   `json:"exemplar,omitempty"`
+	Exemplars []*jsonExemplar `json:"exemplars,omitempty"`
+}
+
+// MarshalJSON encodes d as JSON.
+func (d *Distribution) MarshalJSON() ([]byte, error) {
+	jd := jsonDistribution{
+		Count:                 d.Count,
+		Sum:                   jsonFloat(d.Sum),
+		SumOfSquaredDeviation: jsonFloat(d.SumOfSquaredDeviation),
+		BucketOptions:         d.BucketOptions,
+	}
+	for _, b := range d.Buckets {
+		jb := jsonBucket{
+			Count:     b.Count,
+			Exemplars: toJSONExemplars(b.Exemplars),
+		}
+		if b.Exemplar != nil {
+			jb.Exemplar = toJSONExemplar(b.Exemplar)
+		}
+		jd.Buckets = append(jd.Buckets, jb)
+	}
+	return json.Marshal(jd)
+}
+
+// UnmarshalJSON decodes d from JSON encoded by MarshalJSON.
+func (d *Distribution) UnmarshalJSON(b []byte) error {
+	var jd jsonDistribution
+	if err := json.Unmarshal(b, &jd); err != nil {
+		return err
+	}
+	*d = Distribution{
+		Count:                 jd.Count,
+		Sum:                   float64(jd.Sum),
+		SumOfSquaredDeviation: float64(jd.SumOfSquaredDeviation),
+		BucketOptions:         jd.BucketOptions,
+	}
+	for _, jb := range jd.Buckets {
+		b := Bucket{
+			Count:     jb.Count,
+			Exemplars: fromJSONExemplars(jb.Exemplars),
+		}
+		if jb.Exemplar != nil {
+			b.Exemplar = fromJSONExemplar(jb.Exemplar)
+		}
+		d.Buckets = append(d.Buckets, b)
+	}
+	return nil
+}
+
+// MarshalJSON encodes o as JSON.
+func (o *BucketOptions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Bounds []float64 `json:"bounds"`
+	}{o.Bounds})
+}
+
+// UnmarshalJSON decodes o from JSON encoded by MarshalJSON.
+func (o *BucketOptions) UnmarshalJSON(b []byte) error {
+	var jo struct {
+		Bounds []float64 `json:"bounds"`
+	}
+	if err := json.Unmarshal(b, &jo); err != nil {
+		return err
+	}
+	o.Bounds = jo.Bounds
+	return nil
+}
+
+type jsonSummary struct {
+	Count          int64        `json:"count"`
+	Sum            jsonFloat    `json:"sum"`
+	HasCountAndSum bool         `json:"has_count_and_sum"`
+	Snapshot       jsonSnapshot `json:"snapshot"`
+}
+
+type jsonSnapshot struct {
+	Count       int64            `json:"count"`
+	Sum         jsonFloat        `json:"sum"`
+	Percentiles []jsonPercentile `json:"percentiles,omitempty"`
+}
+
+type jsonPercentile struct {
+	Percentile float64   `json:"percentile"`
+	Value      jsonFloat `json:"value"`
+}
+
+// MarshalJSON encodes s as JSON. The percentiles of the snapshot are encoded
+// as a list sorted by percentile.
+func (s *Summary) MarshalJSON() ([]byte, error) {
+	js := jsonSummary{
+		Count:          s.Count,
+		Sum:            jsonFloat(s.Sum),
+		HasCountAndSum: s.HasCountAndSum,
+		Snapshot: jsonSnapshot{
+			Count: s.Snapshot.Count,
+			Sum:   jsonFloat(s.Snapshot.Sum),
+		},
+	}
+	for p, v := range s.Snapshot.Percentiles {
+		js.Snapshot.Percentiles = append(js.Snapshot.Percentiles, jsonPercentile{Percentile: p, Value: jsonFloat(v)})
+	}
+	sort.Slice(js.Snapshot.Percentiles, func(i, j int) bool {
+		return js.Snapshot.Percentiles[i].Percentile < js.Snapshot.Percentiles[j].Percentile
+	})
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON decodes s from JSON encoded by MarshalJSON.
+func (s *Summary) UnmarshalJSON(b []byte) error {
+	var js jsonSummary
+	if err := json.Unmarshal(b, &js); err != nil {
+		return err
+	}
+	*s = Summary{
+		Count:          js.Count,
+		Sum:            float64(js.Sum),
+		HasCountAndSum: js.HasCountAndSum,
+		Snapshot: Snapshot{
+			Count: js.Snapshot.Count,
+			Sum:   float64(js.Snapshot.Sum),
+		},
+	}
+	if js.Snapshot.Percentiles != nil {
+		s.Snapshot.Percentiles = make(map[float64]float64, len(js.Snapshot.Percentiles))
+		for _, p := range js.Snapshot.Percentiles {
+			s.Snapshot.Percentiles[p.Percentile] = float64(p.Value)
+		}
+	}
+	return nil
+}
+
+type jsonExemplar struct {
+	Value       jsonFloat         `json:"value"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Attachments map[string]string `json:"attachments,omitempty"`
+}
+
+func toJSONExemplar(e *exemplar.Exemplar) *jsonExemplar {
+	return &jsonExemplar{
+		Value:       jsonFloat(e.Value),
+		Timestamp:   e.Timestamp,
+		Attachments: e.Attachments,
+	}
+}
+
+func fromJSONExemplar(je *jsonExemplar) *exemplar.Exemplar {
+	return &exemplar.Exemplar{
+		Value:       float64(je.Value),
+		Timestamp:   je.Timestamp,
+		Attachments: je.Attachments,
+	}
+}
+
+func toJSONExemplars(es []*exemplar.Exemplar) []*jsonExemplar {
+	var jes []*jsonExemplar
+	for _, e := range es {
+		jes = append(jes, toJSONExemplar(e))
+	}
+	return jes
+}
+
+func fromJSONExemplars(jes []*jsonExemplar) []*exemplar.Exemplar {
+	var es []*exemplar.Exemplar
+	for _, je := range jes {
+		es = append(es, fromJSONExemplar(je))
+	}
+	return es
+}
+
+// jsonFloat is a float64 encoded as a JSON number if it is finite, or else
+// as one of the strings "NaN", "+Inf" and "-Inf".
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsNaN(v):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(v, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(v, -1):
+		return []byte(`"-Inf"`), nil
+	}
+	return strconv.AppendFloat(nil, v, 'g', -1, 64), nil
+}
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		switch s {
+		case "NaN":
+			*f = jsonFloat(math.NaN())
+		case "+Inf":
+			*f = jsonFloat(math.Inf(1))
+		case "-Inf":
+			*f = jsonFloat(math.Inf(-1))
+		default:
+			return fmt.Errorf("metricdata: invalid float64 %q", s)
+		}
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metricdata
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/exemplar"
+	"go.opencensus.io/resource"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	now := time.Unix(1500000000, 5).UTC()
+	e := &exemplar.Exemplar{Value: 1.5, Timestamp: now, Attachments: exemplar.Attachments{"trace_id": "abcd"}}
+	metrics := []*Metric{
+		{
+			Descriptor: Descriptor{Name: "int64", Unit: UnitBytes, Type: TypeCumulativeInt64, LabelKeys: []string{"k1", "k2"}},
+			Resource:   &resource.Resource{Type: "container", Labels: map[string]string{"pod": "p1"}},
+			TimeSeries: []*TimeSeries{{
+				LabelValues: []LabelValue{NewLabelValue(""), {}},
+				Points:      []Point{NewInt64Point(now, math.MaxInt64)},
+				StartTime:   now.Add(-time.Hour),
+			}},
+		},
+		{
+			Descriptor: Descriptor{Name: "float64", Description: "d", Type: TypeGaugeFloat64},
+			TimeSeries: []*TimeSeries{{
+				Points: []Point{
+					{Time: now, Value: 0.1, Exemplars: []*exemplar.Exemplar{e}},
+					NewFloat64Point(now, math.Inf(-1)),
+				},
+			}},
+		},
+		{
+			Descriptor: Descriptor{Name: "distribution", Unit: UnitMilliseconds, Type: TypeCumulativeDistribution},
+			TimeSeries: []*TimeSeries{{
+				Points: []Point{NewDistributionPoint(now, &Distribution{
+					Count:                 3,
+					Sum:                   6,
+					SumOfSquaredDeviation: 2,
+					BucketOptions:         &BucketOptions{Bounds: []float64{2}},
+					Buckets:               []Bucket{{Count: 1, Exemplar: e, Exemplars: []*exemplar.Exemplar{e}}, {Count: 2}},
+				})},
+				StartTime: now,
+			}},
+		},
+		{
+			Descriptor: Descriptor{Name: "summary", Type: TypeSummary},
+			TimeSeries: []*TimeSeries{{
+				Points: []Point{NewSummaryPoint(now, &Summary{
+					Count:          10,
+					Sum:            55,
+					HasCountAndSum: true,
+					Snapshot: Snapshot{
+						Count:       2,
+						Sum:         3,
+						Percentiles: map[float64]float64{50: 1, 99.9: 2},
+					},
+				})},
+				StartTime: now,
+			}},
+		},
+	}
+	b, err := json.Marshal(metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*Metric
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) = %v", b, err)
+	}
+	if diff := cmp.Diff(got, metrics); diff != "" {
+		t.Errorf("round trip of %s -got +want: %s", b, diff)
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	m := &Metric{
+		Descriptor: Descriptor{Name: "m", Unit: UnitDimensionless, Type: TypeGaugeFloat64, LabelKeys: []string{"k1", "k2"}},
+		TimeSeries: []*TimeSeries{{
+			LabelValues: []LabelValue{NewLabelValue("v1"), {}},
+			Points:      []Point{NewFloat64Point(time.Unix(0, 0).UTC(), math.NaN())},
+		}},
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"descriptor":{"name":"m","unit":"1","type":"TypeGaugeFloat64","label_keys":["k1","k2"]},` +
+		`"time_series":[{"label_values":["v1",null],"points":[{"time":"1970-01-01T00:00:00Z","float64":"NaN"}],"start_time":"0001-01-01T00:00:00Z"}]}`
+	if string(b) != want {
+		t.Errorf("json.Marshal() = %s; want %s", b, want)
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	var m Metric
+	if err := json.Unmarshal([]byte(`{"descriptor":{"name":"m","type":"TypeUnknown"}}`), &m); err == nil {
+		t.Error("json.Unmarshal() of an unknown metric type succeeded; want an error")
+	}
+	points := []string{
+		`{"time":"1970-01-01T00:00:00Z"}`,
+		`{"time":"1970-01-01T00:00:00Z","int64":1,"float64":1}`,
+		`{"time":"1970-01-01T00:00:00Z","float64":"Infinity"}`,
+	}
+	for _, s := range points {
+		var p Point
+		if err := json.Unmarshal([]byte(s), &p); err == nil {
+			t.Errorf("json.Unmarshal(%s) succeeded; want an error", s)
+		}
+	}
+}
@@ -27,6 +27,9 @@ type Point struct {
 	// Value is the value of this point. Prefer using ReadValue to switching on
 	// the value type, since new value types might be added.
 	Value interface{}
+	// Exemplars are example measurements of an int64 or float64 point, if
+	// any. The exemplars of a distribution point are in its buckets.
+	Exemplars []*exemplar.Exemplar
 }
 
 //go:generate stringer -type ValueType
@@ -145,6 +148,9 @@ type Bucket struct {
 	Count int64
 	// Exemplar associated with this bucket (if any).
 	Exemplar *exemplar.Exemplar
+	// Exemplars are all the exemplars associated with this bucket, if more
+	// than one may be retained. Exemplar is the last of them.
+	Exemplars []*exemplar.Exemplar
 }
 
 // Summary is a representation of percentiles.
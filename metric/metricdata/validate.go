@@ -0,0 +1,96 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metricdata
+
+import "fmt"
+
+// Validate checks that m satisfies the invariants of the metric data model:
+// every time series has a label value for each label key, every point holds
+// a non-nil value of the type of the metric, and every distribution has
+// strictly increasing bounds and as many buckets as they define, whose
+// counts sum to its count.
+func (m *Metric) Validate() error {
+	for i, ts := range m.TimeSeries {
+		if len(ts.LabelValues) != len(m.Descriptor.LabelKeys) {
+			return fmt.Errorf("metricdata: metric %q: time series %d has %d label values for %d label keys",
+				m.Descriptor.Name, i, len(ts.LabelValues), len(m.Descriptor.LabelKeys))
+		}
+		for j, p := range ts.Points {
+			if err := validatePoint(m.Descriptor.Type, p); err != nil {
+				return fmt.Errorf("metricdata: metric %q: time series %d: point %d: %v", m.Descriptor.Name, i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validatePoint(t Type, p Point) error {
+	var ok bool
+	switch t {
+	case TypeGaugeInt64, TypeCumulativeInt64:
+		_, ok = p.Value.(int64)
+	case TypeGaugeFloat64, TypeCumulativeFloat64:
+		_, ok = p.Value.(float64)
+	case TypeGaugeDistribution, TypeCumulativeDistribution:
+		var d *Distribution
+		if d, ok = p.Value.(*Distribution); ok {
+			if d == nil {
+				return fmt.Errorf("nil distribution")
+			}
+			return validateDistribution(d)
+		}
+	case TypeSummary:
+		var s *Summary
+		if s, ok = p.Value.(*Summary); ok && s == nil {
+			return fmt.Errorf("nil summary")
+		}
+	default:
+		return fmt.Errorf("unknown metric type %v", t)
+	}
+	if !ok {
+		return fmt.Errorf("value of type %T in a metric of type %v", p.Value, t)
+	}
+	return nil
+}
+
+func validateDistribution(d *Distribution) error {
+	if d.Count < 0 {
+		return fmt.Errorf("negative distribution count %d", d.Count)
+	}
+	if d.Count == 0 && (d.Sum != 0 || d.SumOfSquaredDeviation != 0) {
+		return fmt.Errorf("distribution with no values has sum %v and sum of squared deviation %v", d.Sum, d.SumOfSquaredDeviation)
+	}
+	if d.BucketOptions == nil {
+		return nil
+	}
+	bounds := d.BucketOptions.Bounds
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			return fmt.Errorf("distribution bounds %v are not strictly increasing", bounds)
+		}
+	}
+	if len(d.Buckets) != len(d.BucketOptions.Bounds)+1 {
+		return fmt.Errorf("distribution has %d buckets for %d bounds", len(d.Buckets), len(d.BucketOptions.Bounds))
+	}
+	var count int64
+	for _, b := range d.Buckets {
+		count += b.Count
+	}
+	if count != d.Count {
+		return fmt.Errorf("distribution bucket counts sum to %d, not to the count %d", count, d.Count)
+	}
+	return nil
+}
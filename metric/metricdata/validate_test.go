@@ -0,0 +1,82 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metricdata
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	metric := func(typ Type, keys []string, labels []LabelValue, v interface{}) *Metric {
+		return &Metric{
+			Descriptor: Descriptor{Name: "m", Type: typ, LabelKeys: keys},
+			TimeSeries: []*TimeSeries{{
+				LabelValues: labels,
+				Points:      []Point{{Time: now, Value: v}},
+			}},
+		}
+	}
+	dist := func(count int64, buckets ...int64) *Distribution {
+		d := &Distribution{Count: count, Sum: float64(count), BucketOptions: &BucketOptions{Bounds: []float64{1}}}
+		for _, c := range buckets {
+			d.Buckets = append(d.Buckets, Bucket{Count: c})
+		}
+		return d
+	}
+	tests := []struct {
+		name    string
+		m       *Metric
+		wantErr string
+	}{
+		{"int64", metric(TypeCumulativeInt64, []string{"k"}, []LabelValue{{}}, int64(1)), ""},
+		{"float64", metric(TypeGaugeFloat64, nil, nil, 1.5), ""},
+		{"distribution", metric(TypeCumulativeDistribution, nil, nil, dist(3, 1, 2)), ""},
+		{"distribution without buckets", metric(TypeGaugeDistribution, nil, nil, &Distribution{Count: 2, Sum: 1}), ""},
+		{"summary", metric(TypeSummary, nil, nil, &Summary{}), ""},
+		{"missing label value", metric(TypeGaugeInt64, []string{"k1", "k2"}, []LabelValue{{}}, int64(1)), "1 label values for 2 label keys"},
+		{"extra label value", metric(TypeGaugeInt64, nil, []LabelValue{{}}, int64(1)), "1 label values for 0 label keys"},
+		{"float64 in int64 metric", metric(TypeCumulativeInt64, nil, nil, 1.5), "float64 in a metric of type TypeCumulativeInt64"},
+		{"int64 in distribution metric", metric(TypeCumulativeDistribution, nil, nil, int64(1)), "int64 in a metric of type TypeCumulativeDistribution"},
+		{"bucket counts", metric(TypeCumulativeDistribution, nil, nil, dist(4, 1, 2)), "sum to 3, not to the count 4"},
+		{"bucket number", metric(TypeCumulativeDistribution, nil, nil, dist(1, 1)), "1 buckets for 1 bounds"},
+		{"empty distribution sum", metric(TypeCumulativeDistribution, nil, nil, &Distribution{Sum: 1}), "no values has sum 1"},
+		{"nil distribution", metric(TypeCumulativeDistribution, nil, nil, (*Distribution)(nil)), "nil distribution"},
+		{"nil summary", metric(TypeSummary, nil, nil, (*Summary)(nil)), "nil summary"},
+		{"decreasing bounds", metric(TypeCumulativeDistribution, nil, nil, &Distribution{
+			BucketOptions: &BucketOptions{Bounds: []float64{2, 1}},
+			Buckets:       []Bucket{{}, {}, {}},
+		}), "not strictly increasing"},
+		{"equal bounds", metric(TypeCumulativeDistribution, nil, nil, &Distribution{
+			BucketOptions: &BucketOptions{Bounds: []float64{1, 1}},
+			Buckets:       []Bucket{{}, {}, {}},
+		}), "not strictly increasing"},
+	}
+	for _, tt := range tests {
+		err := tt.m.Validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: Validate() = %v; want nil", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Validate() = %v; want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -15,23 +15,60 @@
 package metric
 
 import (
-	"go.opencensus.io/metric/metricdata"
 	"log"
+	"sort"
 	"time"
+
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/resource"
 )
 
-// Registry creates and manages a set of gauges.
+// Registry creates and manages a set of gauges and histograms.
 // External synchronization is required if you want to add gauges to the same
 // registry from multiple goroutines.
 type Registry struct {
-	gauges map[string]*gauge
+	gauges    map[string]*gauge
+	constKeys []string
+	constVals []metricdata.LabelValue
+	resource  *resource.Resource
+}
+
+// RegistryOption configures a Registry.
+type RegistryOption func(*Registry)
+
+// WithConstLabels adds the given labels, such as a version or a region, to
+// every time series of the registry. Their keys, sorted, are prepended to the
+// label keys of every metric.
+func WithConstLabels(labels map[string]string) RegistryOption {
+	return func(r *Registry) {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			r.constKeys = append(r.constKeys, k)
+			r.constVals = append(r.constVals, metricdata.NewLabelValue(labels[k]))
+		}
+	}
+}
+
+// WithResource sets the resource of every metric of the registry.
+func WithResource(res *resource.Resource) RegistryOption {
+	return func(r *Registry) {
+		r.resource = res
+	}
 }
 
 // NewRegistry initializes a new Registry.
-func NewRegistry() *Registry {
-	return &Registry{
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
 		gauges: make(map[string]*gauge),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // AddFloat64Gauge creates and adds a new float64-valued gauge to this registry.
@@ -41,6 +78,7 @@ func (r *Registry) AddFloat64Gauge(name, description string, unit metricdata.Uni
 			isFloat: true,
 		},
 	}
+	f.g.desc.Type = metricdata.TypeGaugeFloat64
 	r.initGauge(&f.g, labelKeys, name, description, unit)
 	return f
 }
@@ -48,14 +86,34 @@ func (r *Registry) AddFloat64Gauge(name, description string, unit metricdata.Uni
 // AddInt64Gauge creates and adds a new int64-valued gauge to this registry.
 func (r *Registry) AddInt64Gauge(name, description string, unit metricdata.Unit, labelKeys ...string) *Int64Gauge {
 	i := &Int64Gauge{}
+	i.g.desc.Type = metricdata.TypeGaugeInt64
 	r.initGauge(&i.g, labelKeys, name, description, unit)
 	return i
 }
 
+// AddHistogram creates and adds a new histogram to this registry. Its buckets
+// are delimited by bounds, which must be increasing; see ExponentialBounds.
+func (r *Registry) AddHistogram(name, description string, unit metricdata.Unit, bounds []float64, labelKeys ...string) *Histogram {
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			log.Panicf("Histogram %s bounds are not increasing", name)
+		}
+	}
+	h := &Histogram{
+		g: gauge{
+			isFloat: true,
+		},
+		bounds: append([]float64(nil), bounds...),
+	}
+	h.g.desc.Type = metricdata.TypeCumulativeDistribution
+	r.initGauge(&h.g, labelKeys, name, description, unit)
+	return h
+}
+
 func (r *Registry) initGauge(g *gauge, labelKeys []string, name string, description string, unit metricdata.Unit) *gauge {
 	existing, ok := r.gauges[name]
 	if ok {
-		if existing.isFloat != g.isFloat {
+		if existing.isFloat != g.isFloat || existing.desc.Type != g.desc.Type {
 			log.Panicf("Gauge with name %s already exists with a different type", name)
 		}
 	}
@@ -65,8 +123,12 @@ func (r *Registry) initGauge(g *gauge, labelKeys []string, name string, descript
 		Name:        name,
 		Description: description,
 		Unit:        unit,
+		Type:        g.desc.Type,
 		LabelKeys:   labelKeys,
 	}
+	if len(r.constKeys) > 0 {
+		g.desc.LabelKeys = append(append([]string(nil), r.constKeys...), labelKeys...)
+	}
 	r.gauges[name] = g
 	return g
 }
@@ -75,7 +137,14 @@ func (r *Registry) initGauge(g *gauge, labelKeys []string, name string, descript
 func (r *Registry) ReadAll() []*metricdata.Metric {
 	ms := make([]*metricdata.Metric, 0, len(r.gauges))
 	for _, g := range r.gauges {
-		ms = append(ms, g.read())
+		m := g.read()
+		m.Resource = r.resource
+		if len(r.constVals) > 0 {
+			for _, ts := range m.TimeSeries {
+				ts.LabelValues = append(append([]metricdata.LabelValue(nil), r.constVals...), ts.LabelValues...)
+			}
+		}
+		ms = append(ms, m)
 	}
 	return ms
 }
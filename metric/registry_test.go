@@ -0,0 +1,86 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/resource"
+)
+
+func TestRegistryOptions(t *testing.T) {
+	res := &resource.Resource{Type: "container", Labels: map[string]string{"pod": "p1"}}
+	r := NewRegistry(
+		WithConstLabels(map[string]string{"version": "v1", "region": "eu"}),
+		WithResource(res))
+	g := r.AddInt64Gauge("g", "", metricdata.UnitDimensionless, "k1")
+	g.GetEntry(metricdata.NewLabelValue("v")).Set(1)
+	h := r.AddHistogram("h", "", metricdata.UnitDimensionless, []float64{1})
+	h.GetEntry().Record(2)
+
+	metrics := make(map[string]*metricdata.Metric)
+	for _, m := range r.ReadAll() {
+		metrics[m.Descriptor.Name] = m
+	}
+	tests := []struct {
+		name   string
+		keys   []string
+		labels []metricdata.LabelValue
+	}{
+		{
+			name:   "g",
+			keys:   []string{"region", "version", "k1"},
+			labels: []metricdata.LabelValue{metricdata.NewLabelValue("eu"), metricdata.NewLabelValue("v1"), metricdata.NewLabelValue("v")},
+		},
+		{
+			name:   "h",
+			keys:   []string{"region", "version"},
+			labels: []metricdata.LabelValue{metricdata.NewLabelValue("eu"), metricdata.NewLabelValue("v1")},
+		},
+	}
+	for _, tt := range tests {
+		m := metrics[tt.name]
+		if m.Resource != res {
+			t.Errorf("%s: resource = %v; want %v", tt.name, m.Resource, res)
+		}
+		if diff := cmp.Diff(m.Descriptor.LabelKeys, tt.keys); diff != "" {
+			t.Errorf("%s: label keys -got +want: %s", tt.name, diff)
+		}
+		if diff := cmp.Diff(m.TimeSeries[0].LabelValues, tt.labels); diff != "" {
+			t.Errorf("%s: label values -got +want: %s", tt.name, diff)
+		}
+	}
+
+	// Reading again does not prepend the constant labels twice.
+	m := r.ReadAll()[0]
+	if got, want := len(m.TimeSeries[0].LabelValues), len(m.Descriptor.LabelKeys); got != want {
+		t.Errorf("%d label values; want %d", got, want)
+	}
+}
+
+func TestRegistryValidMetrics(t *testing.T) {
+	r := NewRegistry(WithConstLabels(map[string]string{"version": "v1"}))
+	r.AddFloat64Gauge("float64", "", metricdata.UnitDimensionless, "k").GetEntry(metricdata.NewLabelValue("v")).Set(1.5)
+	r.AddInt64Gauge("int64", "", metricdata.UnitDimensionless).GetEntry().Set(2)
+	r.AddHistogram("histogram", "", metricdata.UnitMilliseconds, []float64{1, 10}).GetEntry().Record(5)
+	for _, m := range r.ReadAll() {
+		if err := m.Validate(); err != nil {
+			t.Errorf("%s: %v", m.Descriptor.Name, err)
+		}
+	}
+}
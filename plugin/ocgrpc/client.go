@@ -16,6 +16,7 @@ package ocgrpc
 
 import (
 	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
 	"golang.org/x/net/context"
 
 	"google.golang.org/grpc/stats"
@@ -29,6 +30,11 @@ type ClientHandler struct {
 	// StartOptions.SpanKind will always be set to trace.SpanKindClient
 	// for spans started by this handler.
 	StartOptions trace.StartOptions
+
+	// Propagation defines how span contexts are propagated in the outgoing
+	// RPC metadata. If unspecified, &BinaryFormat{} is used, which only
+	// propagates the span context in the grpc-trace-bin metadata key.
+	Propagation propagation.TextMapFormat
 }
 
 // HandleConn exists to satisfy gRPC stats.Handler.
@@ -343,3 +343,24 @@ func containsRow(rows []*view.Row, r *view.Row) bool {
 	}
 	return false
 }
+
+func TestClientHandler_statsTagRPC_NoPropagation(t *testing.T) {
+	kPropagated, _ := tag.NewKey("propagated")
+	kLocal, _ := tag.NewKey("local")
+	ctx, _ := tag.New(context.Background(),
+		tag.Insert(kPropagated, "v1"),
+		tag.Insert(kLocal, "v2", tag.WithTTL(tag.TTLNoPropagation)))
+
+	ch := &ClientHandler{}
+	ctx = ch.statsTagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/package.service/method"})
+	m, err := tag.Decode(stats.OutgoingTags(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.Value(kPropagated); got != "v1" {
+		t.Errorf("propagated tag = %q; want %q", got, "v1")
+	}
+	if _, ok := m.Value(kLocal); ok {
+		t.Errorf("tag %q with TTLNoPropagation was propagated", kLocal.Name())
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocgrpc
+
+import (
+	"strings"
+
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+const traceContextKey = "grpc-trace-bin"
+
+var defaultFormat propagation.TextMapFormat = &BinaryFormat{}
+
+// BinaryFormat implements propagation.TextMapFormat to propagate span
+// contexts in the grpc-trace-bin metadata key, using the binary format
+// of go.opencensus.io/trace/propagation.
+//
+// To propagate span contexts using the W3C traceparent and tracestate
+// metadata keys instead, set the Propagation field of ClientHandler and
+// ServerHandler to &tracecontext.HTTPFormat{} from
+// go.opencensus.io/plugin/ochttp/propagation/tracecontext.
+type BinaryFormat struct {
+	// IncludeTracestate makes the Tracestate of the span context be
+	// propagated along with it. Receivers that do not support it
+	// ignore it.
+	IncludeTracestate bool
+}
+
+var _ propagation.TextMapFormat = (*BinaryFormat)(nil)
+
+// SpanContextFromCarrier extracts a span context from the grpc-trace-bin key.
+func (f *BinaryFormat) SpanContextFromCarrier(c propagation.TextMapCarrier) (sc trace.SpanContext, ok bool) {
+	traceContext := c.Get(traceContextKey)
+	if len(traceContext) == 0 {
+		return trace.SpanContext{}, false
+	}
+	// Metadata with keys ending in -bin are actually binary. They are base64
+	// encoded before being put on the wire, see:
+	// https://github.com/grpc/grpc-go/blob/08d6261/Documentation/grpc-metadata.md#storing-binary-data-in-metadata
+	return propagation.FromBinary([]byte(traceContext[0]))
+}
+
+// SpanContextToCarrier sets the grpc-trace-bin key to the binary encoding of sc.
+func (f *BinaryFormat) SpanContextToCarrier(sc trace.SpanContext, c propagation.TextMapCarrier) {
+	var b []byte
+	if f.IncludeTracestate {
+		b = propagation.BinaryWithTracestate(sc)
+	} else {
+		b = propagation.Binary(sc)
+	}
+	c.Set(traceContextKey, string(b))
+}
+
+// metadataCarrier adapts metadata.MD to propagation.TextMapCarrier.
+// Keys are lowercased, as gRPC metadata keys are.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) []string {
+	return m[strings.ToLower(key)]
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	m[strings.ToLower(key)] = []string{value}
+}
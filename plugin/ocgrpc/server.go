@@ -16,6 +16,7 @@ package ocgrpc
 
 import (
 	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
 	"golang.org/x/net/context"
 
 	"google.golang.org/grpc/stats"
@@ -51,6 +52,11 @@ type ServerHandler struct {
 	// StartOptions.SpanKind will always be set to trace.SpanKindServer
 	// for spans started by this handler.
 	StartOptions trace.StartOptions
+
+	// Propagation defines how span contexts are extracted from the incoming
+	// RPC metadata. If unspecified, &BinaryFormat{} is used, which reads the
+	// span context from the grpc-trace-bin metadata key.
+	Propagation propagation.TextMapFormat
 }
 
 var _ stats.Handler = (*ServerHandler)(nil)
@@ -27,7 +27,19 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const traceContextKey = "grpc-trace-bin"
+func (c *ClientHandler) format() propagation.TextMapFormat {
+	if c.Propagation == nil {
+		return defaultFormat
+	}
+	return c.Propagation
+}
+
+func (s *ServerHandler) format() propagation.TextMapFormat {
+	if s.Propagation == nil {
+		return defaultFormat
+	}
+	return s.Propagation
+}
 
 // TagRPC creates a new trace span for the client side of the RPC.
 //
@@ -39,8 +51,15 @@ func (c *ClientHandler) traceTagRPC(ctx context.Context, rti *stats.RPCTagInfo)
 	ctx, span := trace.StartSpan(ctx, name,
 		trace.WithSampler(c.StartOptions.Sampler),
 		trace.WithSpanKind(trace.SpanKindClient)) // span is ended by traceHandleRPC
-	traceContextBinary := propagation.Binary(span.SpanContext())
-	return metadata.AppendToOutgoingContext(ctx, traceContextKey, string(traceContextBinary))
+	md := metadataCarrier{}
+	c.format().SpanContextToCarrier(span.SpanContext(), md)
+	kv := make([]string, 0, 2*len(md))
+	for k, vs := range md {
+		for _, v := range vs {
+			kv = append(kv, k, v)
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
 }
 
 // TagRPC creates a new trace span for the server side of the RPC.
@@ -53,24 +72,13 @@ func (s *ServerHandler) traceTagRPC(ctx context.Context, rti *stats.RPCTagInfo)
 	md, _ := metadata.FromIncomingContext(ctx)
 	name := strings.TrimPrefix(rti.FullMethodName, "/")
 	name = strings.Replace(name, "/", ".", -1)
-	traceContext := md[traceContextKey]
-	var (
-		parent     trace.SpanContext
-		haveParent bool
-	)
-	if len(traceContext) > 0 {
-		// Metadata with keys ending in -bin are actually binary. They are base64
-		// encoded before being put on the wire, see:
-		// https://github.com/grpc/grpc-go/blob/08d6261/Documentation/grpc-metadata.md#storing-binary-data-in-metadata
-		traceContextBinary := []byte(traceContext[0])
-		parent, haveParent = propagation.FromBinary(traceContextBinary)
-		if haveParent && !s.IsPublicEndpoint {
-			ctx, _ := trace.StartSpanWithRemoteParent(ctx, name, parent,
-				trace.WithSpanKind(trace.SpanKindServer),
-				trace.WithSampler(s.StartOptions.Sampler),
-			)
-			return ctx
-		}
+	parent, haveParent := s.format().SpanContextFromCarrier(metadataCarrier(md))
+	if haveParent && !s.IsPublicEndpoint {
+		ctx, _ := trace.StartSpanWithRemoteParent(ctx, name, parent,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithSampler(s.StartOptions.Sampler),
+		)
+		return ctx
 	}
 	ctx, span := trace.StartSpan(ctx, name,
 		trace.WithSpanKind(trace.SpanKindServer),
@@ -15,9 +15,13 @@
 package ocgrpc
 
 import (
+	"reflect"
 	"testing"
 
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
 	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+	"go.opencensus.io/trace/tracestate"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
@@ -44,3 +48,54 @@ func TestClientHandler_traceTagRPC(t *testing.T) {
 		t.Fatal("no metadata")
 	}
 }
+
+func TestTraceTagRPC_propagation(t *testing.T) {
+	ts, err := tracestate.New(nil, tracestate.Entry{Key: "vendor", Value: "priority:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := trace.SpanContext{
+		TraceID:      trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:       trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceOptions: 1,
+		Tracestate:   ts,
+	}
+	tests := []struct {
+		name           string
+		format         propagation.TextMapFormat
+		wantKey        string
+		wantTracestate bool
+	}{
+		{"default", nil, traceContextKey, false},
+		{"binary with tracestate", &BinaryFormat{IncludeTracestate: true}, traceContextKey, true},
+		{"tracecontext", &tracecontext.HTTPFormat{}, "traceparent", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &ClientHandler{Propagation: tt.format}
+			ctx, _ := trace.StartSpanWithRemoteParent(context.Background(), "parent", parent)
+			ctx = ch.traceTagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/foo.Bar/Baz"})
+			clientSpan := trace.FromContext(ctx)
+
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if len(md[tt.wantKey]) == 0 {
+				t.Fatalf("metadata = %v; want key %q", md, tt.wantKey)
+			}
+
+			sh := &ServerHandler{Propagation: tt.format}
+			ctx = sh.traceTagRPC(metadata.NewIncomingContext(context.Background(), md), &stats.RPCTagInfo{FullMethodName: "/foo.Bar/Baz"})
+			sc := trace.FromContext(ctx).SpanContext()
+			if got, want := sc.TraceID, parent.TraceID; got != want {
+				t.Errorf("server TraceID = %v; want %v", got, want)
+			}
+			if got, want := sc.Tracestate != nil, tt.wantTracestate; got != want {
+				t.Fatalf("server has tracestate = %v; want %v", got, want)
+			}
+			if tt.wantTracestate && !reflect.DeepEqual(sc.Tracestate.Entries(), ts.Entries()) {
+				t.Errorf("server tracestate = %v; want %v", sc.Tracestate.Entries(), ts.Entries())
+			}
+			clientSpan.End()
+			trace.FromContext(ctx).End()
+		})
+	}
+}
@@ -61,7 +61,9 @@ type Transport struct {
 	// httptrace package.
 	NewClientTrace func(*http.Request, *trace.Span) *httptrace.ClientTrace
 
-	// TODO: Implement tag propagation for HTTP.
+	// TagPropagation defines how the tags in the request context are
+	// propagated. If unspecified, tags are not propagated.
+	TagPropagation TagFormat
 }
 
 // RoundTrip implements http.RoundTripper, delegating to Base and recording stats and traces for the request.
@@ -70,6 +72,9 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if isHealthEndpoint(req.URL.Path) {
 		return rt.RoundTrip(req)
 	}
+	if t.TagPropagation != nil {
+		req = injectTags(req, t.TagPropagation)
+	}
 	// TODO: remove excessive nesting of http.RoundTrippers here.
 	format := t.Propagation
 	if format == nil {
@@ -0,0 +1,54 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ochttp
+
+import (
+	"net/http"
+
+	"go.opencensus.io/tag"
+)
+
+// TagFormat implementations propagate tag maps in HTTP requests.
+// See go.opencensus.io/plugin/ochttp/propagation/baggage for
+// an implementation.
+//
+// TagsFromRequest extracts a tag map from incoming requests.
+//
+// TagsToRequest modifies the given request to include the given
+// tag map.
+type TagFormat interface {
+	TagsFromRequest(req *http.Request) (m *tag.Map, ok bool)
+	TagsToRequest(m *tag.Map, req *http.Request)
+}
+
+// injectTags returns a copy of req with the tags of its context
+// added to the headers using format.
+func injectTags(req *http.Request, format TagFormat) *http.Request {
+	m := tag.FromContext(req.Context())
+	if m == nil {
+		return req
+	}
+	// TagsToRequest will modify its Request argument, which is
+	// contrary to the contract for http.RoundTripper, so we need to
+	// pass it a copy of the Request and its header.
+	req = req.WithContext(req.Context())
+	header := make(http.Header)
+	for k, v := range req.Header {
+		header[k] = v
+	}
+	req.Header = header
+	format.TagsToRequest(m, req)
+	return req
+}
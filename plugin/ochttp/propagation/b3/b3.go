@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package b3 contains a propagation.HTTPFormat implementation
-// for B3 propagation. See https://github.com/openzipkin/b3-propagation
+// Package b3 contains propagation.HTTPFormat and propagation.TextMapFormat
+// implementations for B3 propagation. See https://github.com/openzipkin/b3-propagation
 // for more details.
 package b3 // import "go.opencensus.io/plugin/ochttp/propagation/b3"
 
@@ -33,7 +33,9 @@ const (
 )
 
 // HTTPFormat implements propagation.HTTPFormat to propagate
-// traces in HTTP headers in B3 propagation format.
+// traces in HTTP headers in B3 propagation format. It also
+// implements propagation.TextMapFormat to propagate traces in
+// any other carrier, such as message queue headers.
 // HTTPFormat skips the X-B3-ParentId and X-B3-Flags headers
 // because there are additional fields not represented in the
 // OpenCensus span context. Spans created from the incoming
@@ -42,19 +44,27 @@ const (
 // span created by OpenCensus as the parent.
 type HTTPFormat struct{}
 
-var _ propagation.HTTPFormat = (*HTTPFormat)(nil)
+var (
+	_ propagation.HTTPFormat    = (*HTTPFormat)(nil)
+	_ propagation.TextMapFormat = (*HTTPFormat)(nil)
+)
 
 // SpanContextFromRequest extracts a B3 span context from incoming requests.
 func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (sc trace.SpanContext, ok bool) {
-	tid, ok := ParseTraceID(req.Header.Get(TraceIDHeader))
+	return f.SpanContextFromCarrier(propagation.HTTPHeaderCarrier(req.Header))
+}
+
+// SpanContextFromCarrier extracts a B3 span context from the carrier.
+func (f *HTTPFormat) SpanContextFromCarrier(c propagation.TextMapCarrier) (sc trace.SpanContext, ok bool) {
+	tid, ok := ParseTraceID(first(c.Get(TraceIDHeader)))
 	if !ok {
 		return trace.SpanContext{}, false
 	}
-	sid, ok := ParseSpanID(req.Header.Get(SpanIDHeader))
+	sid, ok := ParseSpanID(first(c.Get(SpanIDHeader)))
 	if !ok {
 		return trace.SpanContext{}, false
 	}
-	sampled, _ := ParseSampled(req.Header.Get(SampledHeader))
+	sampled, _ := ParseSampled(first(c.Get(SampledHeader)))
 	return trace.SpanContext{
 		TraceID:      tid,
 		SpanID:       sid,
@@ -108,10 +118,23 @@ func ParseSampled(sampled string) (trace.TraceOptions, bool) {
 	}
 }
 
+// first returns the first of values, or "" if there are none.
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
 // SpanContextToRequest modifies the given request to include B3 headers.
 func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
-	req.Header.Set(TraceIDHeader, hex.EncodeToString(sc.TraceID[:]))
-	req.Header.Set(SpanIDHeader, hex.EncodeToString(sc.SpanID[:]))
+	f.SpanContextToCarrier(sc, propagation.HTTPHeaderCarrier(req.Header))
+}
+
+// SpanContextToCarrier modifies the given carrier to include B3 headers.
+func (f *HTTPFormat) SpanContextToCarrier(sc trace.SpanContext, c propagation.TextMapCarrier) {
+	c.Set(TraceIDHeader, hex.EncodeToString(sc.TraceID[:]))
+	c.Set(SpanIDHeader, hex.EncodeToString(sc.SpanID[:]))
 
 	var sampled string
 	if sc.IsSampled() {
@@ -119,5 +142,5 @@ func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Reques
 	} else {
 		sampled = "0"
 	}
-	req.Header.Set(SampledHeader, sampled)
+	c.Set(SampledHeader, sampled)
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
 )
 
 func TestHTTPFormat_FromRequest(t *testing.T) {
@@ -208,3 +209,29 @@ func TestHTTPFormat_ToRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestTextMapFormat(t *testing.T) {
+	sc := trace.SpanContext{
+		TraceID:      trace.TraceID{70, 58, 195, 92, 159, 100, 19, 173, 72, 72, 90, 57, 83, 187, 97, 36},
+		SpanID:       trace.SpanID{0, 32, 0, 0, 0, 0, 0, 1},
+		TraceOptions: trace.TraceOptions(1),
+	}
+	f := &HTTPFormat{}
+	c := propagation.MapCarrier{}
+	f.SpanContextToCarrier(sc, c)
+	want := propagation.MapCarrier{
+		"X-B3-TraceId": "463ac35c9f6413ad48485a3953bb6124",
+		"X-B3-SpanId":  "0020000000000001",
+		"X-B3-Sampled": "1",
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("SpanContextToCarrier() = %v; want %v", c, want)
+	}
+	got, ok := f.SpanContextFromCarrier(c)
+	if !ok {
+		t.Fatal("SpanContextFromCarrier() = false; want true")
+	}
+	if !reflect.DeepEqual(got, sc) {
+		t.Errorf("SpanContextFromCarrier() = %v; want %v", got, sc)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package baggage contains an HTTP propagator of tag maps for the
+// W3C Baggage standard.
+// See https://w3c.github.io/baggage/ for more information.
+package baggage // import "go.opencensus.io/plugin/ochttp/propagation/baggage"
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opencensus.io/tag"
+)
+
+const baggageHeader = "Baggage"
+
+// HTTPFormat implements the W3C Baggage tag propagation format.
+type HTTPFormat struct{}
+
+// TagsFromRequest extracts a tag map from the baggage headers of incoming
+// requests. It returns false if there is no non-empty baggage header or if
+// it is malformed.
+func (f *HTTPFormat) TagsFromRequest(req *http.Request) (m *tag.Map, ok bool) {
+	// Multiple baggage headers are combined according to RFC7230 section 3.2.2.
+	h := strings.Join(req.Header[baggageHeader], ",")
+	if strings.Trim(h, " \t,") == "" {
+		return nil, false
+	}
+	m, err := tag.DecodeBaggage(h)
+	if err != nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// TagsToRequest modifies the given request to include a baggage header
+// carrying the tags of m.
+func (f *HTTPFormat) TagsToRequest(m *tag.Map, req *http.Request) {
+	if h := tag.EncodeBaggage(m); h != "" {
+		req.Header.Set(baggageHeader, h)
+	}
+}
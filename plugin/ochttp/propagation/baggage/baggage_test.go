@@ -0,0 +1,98 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package baggage
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opencensus.io/tag"
+)
+
+func TestHTTPFormat_FromRequest(t *testing.T) {
+	k1, _ := tag.NewKey("k1")
+	k2, _ := tag.NewKey("k2")
+	tests := []struct {
+		name    string
+		headers []string
+		want    map[tag.Key]string
+		wantOk  bool
+	}{
+		{
+			name:   "no header",
+			wantOk: false,
+		},
+		{
+			name:    "single header",
+			headers: []string{"k1=v1,k2=v%202"},
+			want:    map[tag.Key]string{k1: "v1", k2: "v 2"},
+			wantOk:  true,
+		},
+		{
+			name:    "multiple headers",
+			headers: []string{"k1=v1", "k2=v2"},
+			want:    map[tag.Key]string{k1: "v1", k2: "v2"},
+			wantOk:  true,
+		},
+		{
+			name:    "empty header",
+			headers: []string{" ", "\t,"},
+			wantOk:  false,
+		},
+		{
+			name:    "malformed header",
+			headers: []string{"k1"},
+			wantOk:  false,
+		},
+	}
+	f := &HTTPFormat{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			for _, h := range tt.headers {
+				req.Header.Add("baggage", h)
+			}
+			m, ok := f.TagsFromRequest(req)
+			if ok != tt.wantOk {
+				t.Fatalf("HTTPFormat.TagsFromRequest() got ok = %v, want %v", ok, tt.wantOk)
+			}
+			for k, want := range tt.want {
+				if got, _ := m.Value(k); got != want {
+					t.Errorf("HTTPFormat.TagsFromRequest() %v = %q; want %q", k.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPFormat_ToRequest(t *testing.T) {
+	k1, _ := tag.NewKey("k1")
+	k2, _ := tag.NewKey("k2")
+	ctx, _ := tag.New(context.Background(), tag.Upsert(k2, "v,2"), tag.Upsert(k1, "v1"))
+
+	f := &HTTPFormat{}
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	f.TagsToRequest(tag.FromContext(ctx), req)
+	if got, want := req.Header.Get("baggage"), "k1=v1,k2=v%2C2"; got != want {
+		t.Errorf("HTTPFormat.TagsToRequest() header = %q; want %q", got, want)
+	}
+
+	req, _ = http.NewRequest("GET", "http://example.com", nil)
+	f.TagsToRequest(nil, req)
+	if _, ok := req.Header["Baggage"]; ok {
+		t.Errorf("HTTPFormat.TagsToRequest(nil) set the baggage header")
+	}
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package tracecontext contains HTTP propagator for TraceContext standard.
+// Package tracecontext contains HTTP and TextMap propagators for TraceContext standard.
 // See https://github.com/w3c/distributed-tracing for more information.
 package tracecontext // import "go.opencensus.io/plugin/ochttp/propagation/tracecontext"
 
@@ -20,7 +20,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
-	"net/textproto"
 	"regexp"
 	"strings"
 
@@ -40,14 +39,24 @@ const (
 
 var trimOWSRegExp = regexp.MustCompile(trimOWSRegexFmt)
 
-var _ propagation.HTTPFormat = (*HTTPFormat)(nil)
+var (
+	_ propagation.HTTPFormat    = (*HTTPFormat)(nil)
+	_ propagation.TextMapFormat = (*HTTPFormat)(nil)
+)
 
 // HTTPFormat implements the TraceContext trace propagation format.
+// Besides HTTP requests, it can propagate span contexts in any
+// propagation.TextMapCarrier.
 type HTTPFormat struct{}
 
 // SpanContextFromRequest extracts a span context from incoming requests.
 func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (sc trace.SpanContext, ok bool) {
-	h, ok := getRequestHeader(req, traceparentHeader, false)
+	return f.SpanContextFromCarrier(propagation.HTTPHeaderCarrier(req.Header))
+}
+
+// SpanContextFromCarrier extracts a span context from the carrier.
+func (f *HTTPFormat) SpanContextFromCarrier(c propagation.TextMapCarrier) (sc trace.SpanContext, ok bool) {
+	h, ok := getHeader(c, traceparentHeader, false)
 	if !ok {
 		return trace.SpanContext{}, false
 	}
@@ -101,18 +110,18 @@ func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (sc trace.SpanCon
 		return trace.SpanContext{}, false
 	}
 
-	sc.Tracestate = tracestateFromRequest(req)
+	sc.Tracestate = tracestateFromCarrier(c)
 	return sc, true
 }
 
-// getRequestHeader returns a combined header field according to RFC7230 section 3.2.2.
+// getHeader returns a combined header field according to RFC7230 section 3.2.2.
 // If commaSeparated is true, multiple header fields with the same field name using be
 // combined using ",".
 // If no header was found using the given name, "ok" would be false.
 // If more than one headers was found using the given name, while commaSeparated is false,
 // "ok" would be false.
-func getRequestHeader(req *http.Request, name string, commaSeparated bool) (hdr string, ok bool) {
-	v := req.Header[textproto.CanonicalMIMEHeaderKey(name)]
+func getHeader(c propagation.TextMapCarrier, name string, commaSeparated bool) (hdr string, ok bool) {
+	v := c.Get(name)
 	switch len(v) {
 	case 0:
 		return "", false
@@ -128,8 +137,8 @@ func getRequestHeader(req *http.Request, name string, commaSeparated bool) (hdr
 // are resolved.
 // https://github.com/w3c/distributed-tracing/issues/172
 // https://github.com/w3c/distributed-tracing/issues/175
-func tracestateFromRequest(req *http.Request) *tracestate.Tracestate {
-	h, _ := getRequestHeader(req, tracestateHeader, true)
+func tracestateFromCarrier(c propagation.TextMapCarrier) *tracestate.Tracestate {
+	h, _ := getHeader(c, tracestateHeader, true)
 	if h == "" {
 		return nil
 	}
@@ -161,7 +170,7 @@ func tracestateFromRequest(req *http.Request) *tracestate.Tracestate {
 	return ts
 }
 
-func tracestateToRequest(sc trace.SpanContext, req *http.Request) {
+func tracestateToCarrier(sc trace.SpanContext, c propagation.TextMapCarrier) {
 	var pairs = make([]string, 0, len(sc.Tracestate.Entries()))
 	if sc.Tracestate != nil {
 		for _, entry := range sc.Tracestate.Entries() {
@@ -170,18 +179,23 @@ func tracestateToRequest(sc trace.SpanContext, req *http.Request) {
 		h := strings.Join(pairs, ",")
 
 		if h != "" && len(h) <= maxTracestateLen {
-			req.Header.Set(tracestateHeader, h)
+			c.Set(tracestateHeader, h)
 		}
 	}
 }
 
 // SpanContextToRequest modifies the given request to include traceparent and tracestate headers.
 func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
+	f.SpanContextToCarrier(sc, propagation.HTTPHeaderCarrier(req.Header))
+}
+
+// SpanContextToCarrier modifies the given carrier to include traceparent and tracestate headers.
+func (f *HTTPFormat) SpanContextToCarrier(sc trace.SpanContext, c propagation.TextMapCarrier) {
 	h := fmt.Sprintf("%x-%x-%x-%x",
 		[]byte{supportedVersion},
 		sc.TraceID[:],
 		sc.SpanID[:],
 		[]byte{byte(sc.TraceOptions)})
-	req.Header.Set(traceparentHeader, h)
-	tracestateToRequest(sc, req)
+	c.Set(traceparentHeader, h)
+	tracestateToCarrier(sc, c)
 }
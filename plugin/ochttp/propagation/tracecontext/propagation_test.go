@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
 	"go.opencensus.io/trace/tracestate"
 )
 
@@ -265,3 +266,33 @@ func TestHTTPFormatTracestate_ToRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestTextMapFormat(t *testing.T) {
+	sc := trace.SpanContext{
+		TraceID:      traceID,
+		SpanID:       spanID,
+		TraceOptions: traceOpt,
+		Tracestate:   nonDefaultTs,
+	}
+	f := &HTTPFormat{}
+	c := propagation.MapCarrier{}
+	f.SpanContextToCarrier(sc, c)
+	if got, want := c["traceparent"], tpHeader; got != want {
+		t.Errorf("traceparent = %q; want %q", got, want)
+	}
+	if got, want := c["tracestate"], "foo=bar,hello=world   example"; got != want {
+		t.Errorf("tracestate = %q; want %q", got, want)
+	}
+
+	gotSc, ok := f.SpanContextFromCarrier(c)
+	if !ok {
+		t.Fatal("SpanContextFromCarrier() = false; want true")
+	}
+	if !reflect.DeepEqual(gotSc, sc) {
+		t.Errorf("SpanContextFromCarrier() = %v; want %v", gotSc, sc)
+	}
+
+	if _, ok := f.SpanContextFromCarrier(propagation.MapCarrier{}); ok {
+		t.Error("SpanContextFromCarrier(empty) = true; want false")
+	}
+}
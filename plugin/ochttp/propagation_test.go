@@ -23,7 +23,9 @@ import (
 	"testing"
 
 	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/plugin/ochttp/propagation/baggage"
 	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/tag"
 	"go.opencensus.io/trace"
 	"go.opencensus.io/trace/propagation"
 )
@@ -72,3 +74,88 @@ func TestRoundTripAllFormats(t *testing.T) {
 		srv.Close()
 	}
 }
+
+func TestTagPropagation(t *testing.T) {
+	k1, _ := tag.NewKey("k1")
+	k2, _ := tag.NewKey("k2")
+	kLocal, _ := tag.NewKey("local")
+
+	var got *tag.Map
+	srv := httptest.NewServer(&Handler{
+		TagPropagation: &baggage.HTTPFormat{},
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = tag.FromContext(r.Context())
+		}),
+	})
+	defer srv.Close()
+
+	ctx, _ := tag.New(context.Background(),
+		tag.Upsert(k1, "v1"),
+		tag.Upsert(k2, "v 2"),
+		tag.Upsert(kLocal, "user", tag.WithTTL(tag.TTLNoPropagation)))
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req = req.WithContext(ctx)
+	client := &http.Client{Transport: &Transport{TagPropagation: &baggage.HTTPFormat{}}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if _, ok := req.Header["Baggage"]; ok {
+		t.Error("Transport modified the headers of the original request")
+	}
+	for k, want := range map[tag.Key]string{k1: "v1", k2: "v 2"} {
+		if v, _ := got.Value(k); v != want {
+			t.Errorf("server tag %s = %q; want %q", k.Name(), v, want)
+		}
+	}
+	if _, ok := got.Value(kLocal); ok {
+		t.Errorf("server tags = %v; want no %s", got, kLocal.Name())
+	}
+	// Tags added by the client stats transport must not be propagated.
+	if _, ok := got.Value(KeyClientPath); ok {
+		t.Errorf("server tags = %v; want no %s", got, KeyClientPath.Name())
+	}
+}
+
+func TestTagPropagation_merge(t *testing.T) {
+	k1, _ := tag.NewKey("k1")
+	kMiddleware, _ := tag.NewKey("middleware")
+
+	var got *tag.Map
+	h := &Handler{
+		TagPropagation: &baggage.HTTPFormat{},
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = tag.FromContext(r.Context())
+		}),
+	}
+	ctx, _ := tag.New(context.Background(), tag.Upsert(kMiddleware, "mw"))
+
+	tests := []struct {
+		name    string
+		baggage string
+		want    map[tag.Key]string
+	}{
+		{
+			name:    "baggage",
+			baggage: "k1=v1,middleware=remote",
+			want:    map[tag.Key]string{k1: "v1", kMiddleware: "mw"},
+		},
+		{
+			name:    "empty baggage",
+			baggage: " ",
+			want:    map[tag.Key]string{kMiddleware: "mw"},
+		},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "http://example.com", nil).WithContext(ctx)
+		req.Header.Set("Baggage", tt.baggage)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		for k, want := range tt.want {
+			if v, _ := got.Value(k); v != want {
+				t.Errorf("%s: server tag %s = %q; want %q", tt.name, k.Name(), v, want)
+			}
+		}
+	}
+}
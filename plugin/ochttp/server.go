@@ -70,10 +70,23 @@ type Handler struct {
 	// from the information found in the incoming HTTP Request. By default the
 	// name equals the URL Path.
 	FormatSpanName func(*http.Request) string
+
+	// TagPropagation defines how tags are extracted from incoming requests.
+	// If set, the extracted tags are added to the tags in the request context;
+	// tags already in the context take precedence.
+	// If unspecified, tags are not extracted.
+	TagPropagation TagFormat
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var tags addedTags
+	if h.TagPropagation != nil {
+		if m, ok := h.TagPropagation.TagsFromRequest(r); ok {
+			if ctx, err := tag.New(r.Context(), tag.Merge(m)); err == nil {
+				r = r.WithContext(ctx)
+			}
+		}
+	}
 	r, traceEnd := h.startTrace(w, r)
 	defer traceEnd()
 	w, statsEnd := h.startStats(w, r)
@@ -0,0 +1,235 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package procmetrics provides a metric producer reporting statistics of the
+// current process read from the proc filesystem of Linux.
+//
+// The metrics are named as by the process collector of the Prometheus Go
+// client, so that existing dashboards apply to them.
+package procmetrics // import "go.opencensus.io/plugin/procmetrics"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricexport"
+)
+
+// userHZ is the number of clock ticks per second in which the proc
+// filesystem reports times. It is fixed at 100 on all the supported
+// architectures.
+const userHZ = 100
+
+const unitSeconds metricdata.Unit = "s"
+
+// Options configures a Producer.
+type Options struct {
+	// ProcRoot is the mount point of the proc filesystem. If unset, "/proc"
+	// is used.
+	ProcRoot string
+}
+
+// Producer is a metricexport.Producer of the statistics of the current
+// process, read from /proc/self:
+//
+//   - process_cpu_seconds_total: user and system CPU time (cumulative)
+//   - process_cpu_user_seconds_total: user CPU time (cumulative)
+//   - process_cpu_system_seconds_total: system CPU time (cumulative)
+//   - process_resident_memory_bytes: resident memory size (gauge)
+//   - process_virtual_memory_bytes: virtual memory size (gauge)
+//   - process_open_fds: number of open file descriptors (gauge)
+//   - process_max_fds: limit of open file descriptors (gauge)
+//   - process_threads: number of threads (gauge)
+//   - process_start_time_seconds: start time since the Unix epoch (gauge)
+//
+// Metrics whose files cannot be read, for example on other systems than
+// Linux, are omitted.
+type Producer struct {
+	root     string
+	pageSize int64
+	now      func() time.Time
+}
+
+var _ metricexport.Producer = (*Producer)(nil)
+
+// NewProducer creates a Producer of the statistics of the current process.
+func NewProducer(o Options) *Producer {
+	if o.ProcRoot == "" {
+		o.ProcRoot = "/proc"
+	}
+	return &Producer{
+		root:     o.ProcRoot,
+		pageSize: int64(os.Getpagesize()),
+		now:      time.Now,
+	}
+}
+
+// procStat holds the fields of /proc/self/stat reported by the producer.
+type procStat struct {
+	utime      int64 // user CPU time, in clock ticks
+	stime      int64 // system CPU time, in clock ticks
+	numThreads int64
+	startTime  int64 // start time after boot, in clock ticks
+	vsize      int64 // virtual memory size, in bytes
+	rss        int64 // resident set size, in pages
+}
+
+// Read returns the current statistics of the process.
+func (p *Producer) Read() []*metricdata.Metric {
+	now := p.now()
+	var ms []*metricdata.Metric
+
+	stat, err := p.readStat()
+	if err == nil {
+		start := p.startTime(stat)
+		user := float64(stat.utime) / userHZ
+		system := float64(stat.stime) / userHZ
+		ms = append(ms,
+			newMetric("process_cpu_seconds_total", "Total user and system CPU time spent in seconds.",
+				unitSeconds, metricdata.TypeCumulativeFloat64, start, metricdata.NewFloat64Point(now, user+system)),
+			newMetric("process_cpu_user_seconds_total", "Total user CPU time spent in seconds.",
+				unitSeconds, metricdata.TypeCumulativeFloat64, start, metricdata.NewFloat64Point(now, user)),
+			newMetric("process_cpu_system_seconds_total", "Total system CPU time spent in seconds.",
+				unitSeconds, metricdata.TypeCumulativeFloat64, start, metricdata.NewFloat64Point(now, system)),
+			newMetric("process_resident_memory_bytes", "Resident memory size in bytes.",
+				metricdata.UnitBytes, metricdata.TypeGaugeInt64, time.Time{}, metricdata.NewInt64Point(now, stat.rss*p.pageSize)),
+			newMetric("process_virtual_memory_bytes", "Virtual memory size in bytes.",
+				metricdata.UnitBytes, metricdata.TypeGaugeInt64, time.Time{}, metricdata.NewInt64Point(now, stat.vsize)),
+			newMetric("process_threads", "Number of OS threads in the process.",
+				metricdata.UnitDimensionless, metricdata.TypeGaugeInt64, time.Time{}, metricdata.NewInt64Point(now, stat.numThreads)),
+		)
+		if !start.IsZero() {
+			ms = append(ms, newMetric("process_start_time_seconds", "Start time of the process since unix epoch in seconds.",
+				unitSeconds, metricdata.TypeGaugeFloat64, time.Time{}, metricdata.NewFloat64Point(now, float64(start.UnixNano())/1e9)))
+		}
+	}
+	if fds, err := ioutil.ReadDir(p.path("self", "fd")); err == nil {
+		ms = append(ms, newMetric("process_open_fds", "Number of open file descriptors.",
+			metricdata.UnitDimensionless, metricdata.TypeGaugeInt64, time.Time{}, metricdata.NewInt64Point(now, int64(len(fds)))))
+	}
+	if max, err := p.readMaxFDs(); err == nil {
+		ms = append(ms, newMetric("process_max_fds", "Maximum number of open file descriptors.",
+			metricdata.UnitDimensionless, metricdata.TypeGaugeInt64, time.Time{}, metricdata.NewInt64Point(now, max)))
+	}
+	return ms
+}
+
+func (p *Producer) path(elem ...string) string {
+	return filepath.Join(append([]string{p.root}, elem...)...)
+}
+
+// readStat parses /proc/self/stat, as described in proc(5).
+func (p *Producer) readStat() (*procStat, error) {
+	b, err := ioutil.ReadFile(p.path("self", "stat"))
+	if err != nil {
+		return nil, err
+	}
+	// The command name, in parentheses, may contain spaces and parentheses.
+	i := bytes.LastIndexByte(b, ')')
+	if i < 0 {
+		return nil, fmt.Errorf("procmetrics: invalid stat %q", b)
+	}
+	// fields[0] is the third field of the file, the state.
+	fields := strings.Fields(string(b[i+1:]))
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("procmetrics: invalid stat %q", b)
+	}
+	var values [6]int64
+	// The zero-based numbers of the utime, stime, num_threads, starttime,
+	// vsize and rss fields.
+	for j, f := range []int{13, 14, 19, 21, 22, 23} {
+		if values[j], err = strconv.ParseInt(fields[f-2], 10, 64); err != nil {
+			return nil, fmt.Errorf("procmetrics: invalid stat field %d: %v", f+1, err)
+		}
+	}
+	return &procStat{
+		utime:      values[0],
+		stime:      values[1],
+		numThreads: values[2],
+		startTime:  values[3],
+		vsize:      values[4],
+		rss:        values[5],
+	}, nil
+}
+
+// startTime returns the time the process started, from the boot time in
+// /proc/stat, or the zero time if it cannot be read.
+func (p *Producer) startTime(stat *procStat) time.Time {
+	f, err := os.Open(p.path("stat"))
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || fields[0] != "btime" {
+			continue
+		}
+		btime, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		ticks := time.Duration(stat.startTime) * time.Second / userHZ
+		return time.Unix(btime, 0).Add(ticks)
+	}
+	return time.Time{}
+}
+
+// readMaxFDs returns the soft limit of open file descriptors from
+// /proc/self/limits.
+func (p *Producer) readMaxFDs() (int64, error) {
+	b, err := ioutil.ReadFile(p.path("self", "limits"))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) == 0 {
+			break
+		}
+		if fields[0] == "unlimited" {
+			return -1, nil
+		}
+		return strconv.ParseInt(fields[0], 10, 64)
+	}
+	return 0, fmt.Errorf("procmetrics: no open files limit in %s", p.path("self", "limits"))
+}
+
+func newMetric(name, description string, unit metricdata.Unit, typ metricdata.Type, start time.Time, p metricdata.Point) *metricdata.Metric {
+	return &metricdata.Metric{
+		Descriptor: metricdata.Descriptor{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Type:        typ,
+		},
+		TimeSeries: []*metricdata.TimeSeries{{
+			StartTime: start,
+			Points:    []metricdata.Point{p},
+		}},
+	}
+}
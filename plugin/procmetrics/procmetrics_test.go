@@ -0,0 +1,103 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package procmetrics
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+)
+
+func TestProducer(t *testing.T) {
+	p := NewProducer(Options{ProcRoot: "testdata/proc"})
+	p.pageSize = 4096
+	now := time.Unix(1600000000, 0)
+	p.now = func() time.Time { return now }
+	start := time.Unix(1500000010, 0)
+
+	metrics := make(map[string]*metricdata.Metric)
+	for _, m := range p.Read() {
+		metrics[m.Descriptor.Name] = m
+	}
+	tests := []struct {
+		name  string
+		typ   metricdata.Type
+		unit  metricdata.Unit
+		value interface{}
+	}{
+		{"process_cpu_seconds_total", metricdata.TypeCumulativeFloat64, "s", 3.0},
+		{"process_cpu_user_seconds_total", metricdata.TypeCumulativeFloat64, "s", 2.5},
+		{"process_cpu_system_seconds_total", metricdata.TypeCumulativeFloat64, "s", 0.5},
+		{"process_resident_memory_bytes", metricdata.TypeGaugeInt64, metricdata.UnitBytes, int64(256 * 4096)},
+		{"process_virtual_memory_bytes", metricdata.TypeGaugeInt64, metricdata.UnitBytes, int64(1048576)},
+		{"process_threads", metricdata.TypeGaugeInt64, metricdata.UnitDimensionless, int64(8)},
+		{"process_start_time_seconds", metricdata.TypeGaugeFloat64, "s", 1500000010.0},
+		{"process_open_fds", metricdata.TypeGaugeInt64, metricdata.UnitDimensionless, int64(5)},
+		{"process_max_fds", metricdata.TypeGaugeInt64, metricdata.UnitDimensionless, int64(1024)},
+	}
+	if len(metrics) != len(tests) {
+		t.Errorf("got %d metrics; want %d", len(metrics), len(tests))
+	}
+	for _, tt := range tests {
+		m := metrics[tt.name]
+		if m == nil {
+			t.Errorf("%s: no metric", tt.name)
+			continue
+		}
+		if m.Descriptor.Type != tt.typ || m.Descriptor.Unit != tt.unit {
+			t.Errorf("%s: type, unit = %v, %q; want %v, %q", tt.name, m.Descriptor.Type, m.Descriptor.Unit, tt.typ, tt.unit)
+		}
+		ts := m.TimeSeries[0]
+		if got := ts.Points[0].Value; got != tt.value {
+			t.Errorf("%s: value = %v; want %v", tt.name, got, tt.value)
+		}
+		if !ts.Points[0].Time.Equal(now) {
+			t.Errorf("%s: point time = %v; want %v", tt.name, ts.Points[0].Time, now)
+		}
+		wantStart := time.Time{}
+		if tt.typ == metricdata.TypeCumulativeFloat64 {
+			wantStart = start
+		}
+		if !ts.StartTime.Equal(wantStart) {
+			t.Errorf("%s: start time = %v; want %v", tt.name, ts.StartTime, wantStart)
+		}
+	}
+}
+
+func TestProducer_Missing(t *testing.T) {
+	p := NewProducer(Options{ProcRoot: "testdata/missing"})
+	if ms := p.Read(); len(ms) != 0 {
+		t.Errorf("got %d metrics without a proc filesystem; want none", len(ms))
+	}
+}
+
+func TestProducer_Self(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("no proc filesystem")
+	}
+	p := NewProducer(Options{})
+	for _, m := range p.Read() {
+		if m.Descriptor.Name == "process_resident_memory_bytes" {
+			if v := m.TimeSeries[0].Points[0].Value.(int64); v <= 0 {
+				t.Errorf("process_resident_memory_bytes = %d; want > 0", v)
+			}
+			return
+		}
+	}
+	t.Error("no process_resident_memory_bytes metric")
+}
@@ -0,0 +1,172 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package runmetrics provides a metric producer reporting statistics of the
+// Go runtime, such as the heap size, garbage collections and goroutines.
+package runmetrics // import "go.opencensus.io/plugin/runmetrics"
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricexport"
+)
+
+// DefaultMinReadInterval is the default minimum interval between two reads
+// of the memory statistics of the runtime.
+const DefaultMinReadInterval = 5 * time.Second
+
+// Options configures a Producer.
+type Options struct {
+	// Prefix is prepended to the name of every metric, for example
+	// "myapp/". If unset, the metrics are named "process/...".
+	Prefix string
+
+	// MinReadInterval is the minimum interval between two calls to
+	// runtime.ReadMemStats, which stops the world. Reads within it report
+	// the memory statistics of the previous call. If zero,
+	// DefaultMinReadInterval is used.
+	MinReadInterval time.Duration
+}
+
+// Producer is a metricexport.Producer of the statistics of the Go runtime:
+//
+//   - process/heap_alloc: bytes of allocated heap objects (gauge)
+//   - process/heap_inuse: bytes in in-use heap spans (gauge)
+//   - process/heap_objects: number of allocated heap objects (gauge)
+//   - process/gc_count: number of completed GC cycles (cumulative)
+//   - process/gc_pause: distribution of GC pauses, in milliseconds (cumulative)
+//   - process/goroutines: number of goroutines (gauge)
+//   - process/cgo_calls: number of cgo calls (cumulative)
+//   - process/gomaxprocs: value of GOMAXPROCS (gauge)
+type Producer struct {
+	prefix   string
+	interval time.Duration
+	start    time.Time
+
+	mu       sync.Mutex
+	ms       runtime.MemStats
+	lastRead time.Time
+	numGC    uint32 // GC cycles already recorded in gcPause
+	pauses   *metric.Registry
+	gcPause  *metric.HistogramEntry
+
+	// readMemStats and now are replaced in tests.
+	readMemStats func(*runtime.MemStats)
+	now          func() time.Time
+}
+
+var _ metricexport.Producer = (*Producer)(nil)
+
+// gcPauseBounds are the bounds, in milliseconds, of the GC pause distribution.
+var gcPauseBounds = metric.ExponentialBounds(0.01, 2, 16)
+
+// NewProducer creates a Producer of the statistics of the Go runtime.
+func NewProducer(o Options) *Producer {
+	if o.Prefix == "" {
+		o.Prefix = "process/"
+	}
+	if o.MinReadInterval <= 0 {
+		o.MinReadInterval = DefaultMinReadInterval
+	}
+	p := &Producer{
+		prefix:       o.Prefix,
+		interval:     o.MinReadInterval,
+		pauses:       metric.NewRegistry(),
+		readMemStats: runtime.ReadMemStats,
+		now:          time.Now,
+	}
+	p.start = p.now()
+	// Only the GC pauses after the Producer is created are recorded.
+	var ms runtime.MemStats
+	p.readMemStats(&ms)
+	p.numGC = ms.NumGC
+	h := p.pauses.AddHistogram(o.Prefix+"gc_pause", "Distribution of GC stop-the-world pauses", metricdata.UnitMilliseconds, gcPauseBounds)
+	p.gcPause = h.GetEntry()
+	return p
+}
+
+// Read returns the current statistics of the Go runtime. The memory
+// statistics are read at most once per MinReadInterval.
+func (p *Producer) Read() []*metricdata.Metric {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.lastRead.IsZero() || now.Sub(p.lastRead) >= p.interval {
+		p.readMemStats(&p.ms)
+		p.lastRead = now
+		p.recordPauses()
+	}
+
+	ms := []*metricdata.Metric{
+		p.gauge("heap_alloc", "Bytes of allocated heap objects", metricdata.UnitBytes, now, int64(p.ms.HeapAlloc)),
+		p.gauge("heap_inuse", "Bytes in in-use heap spans", metricdata.UnitBytes, now, int64(p.ms.HeapInuse)),
+		p.gauge("heap_objects", "Number of allocated heap objects", metricdata.UnitDimensionless, now, int64(p.ms.HeapObjects)),
+		p.cumulative("gc_count", "Number of completed GC cycles", metricdata.UnitDimensionless, now, int64(p.ms.NumGC)),
+		p.gauge("goroutines", "Number of goroutines", metricdata.UnitDimensionless, now, int64(runtime.NumGoroutine())),
+		p.cumulative("cgo_calls", "Number of cgo calls made by the process", metricdata.UnitDimensionless, now, runtime.NumCgoCall()),
+		p.gauge("gomaxprocs", "Maximum number of CPUs executing simultaneously", metricdata.UnitDimensionless, now, int64(runtime.GOMAXPROCS(0))),
+	}
+	for _, m := range p.pauses.ReadAll() {
+		for _, ts := range m.TimeSeries {
+			ts.StartTime = p.start
+			for i := range ts.Points {
+				ts.Points[i].Time = now
+			}
+		}
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+// recordPauses records the GC pauses since the previous read. The runtime
+// keeps only the most recent 256 of them.
+func (p *Producer) recordPauses() {
+	n := p.ms.NumGC - p.numGC
+	if n > uint32(len(p.ms.PauseNs)) {
+		n = uint32(len(p.ms.PauseNs))
+	}
+	for i := p.ms.NumGC - n; i < p.ms.NumGC; i++ {
+		pause := p.ms.PauseNs[i%uint32(len(p.ms.PauseNs))]
+		p.gcPause.Record(float64(pause) / float64(time.Millisecond))
+	}
+	p.numGC = p.ms.NumGC
+}
+
+func (p *Producer) gauge(name, description string, unit metricdata.Unit, now time.Time, v int64) *metricdata.Metric {
+	return &metricdata.Metric{
+		Descriptor: metricdata.Descriptor{
+			Name:        p.prefix + name,
+			Description: description,
+			Unit:        unit,
+			Type:        metricdata.TypeGaugeInt64,
+		},
+		TimeSeries: []*metricdata.TimeSeries{{
+			Points:    []metricdata.Point{metricdata.NewInt64Point(now, v)},
+			StartTime: now,
+		}},
+	}
+}
+
+func (p *Producer) cumulative(name, description string, unit metricdata.Unit, now time.Time, v int64) *metricdata.Metric {
+	m := p.gauge(name, description, unit, now, v)
+	m.Descriptor.Type = metricdata.TypeCumulativeInt64
+	m.TimeSeries[0].StartTime = p.start
+	return m
+}
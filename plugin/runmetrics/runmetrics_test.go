@@ -0,0 +1,131 @@
+// Copyright 2018, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runmetrics
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+)
+
+func TestProducer(t *testing.T) {
+	p := NewProducer(Options{MinReadInterval: time.Minute})
+	now := time.Unix(1000, 0)
+	p.now = func() time.Time { return now }
+	reads := 0
+	p.readMemStats = func(ms *runtime.MemStats) {
+		reads++
+		ms.HeapAlloc = uint64(reads) * 1024
+		ms.HeapInuse = 4096
+		ms.HeapObjects = 10
+		ms.NumGC = uint32(2 * reads)
+		ms.PauseNs[(ms.NumGC-2)%256] = uint64(time.Millisecond)
+		ms.PauseNs[(ms.NumGC-1)%256] = uint64(3 * time.Millisecond)
+	}
+	p.numGC = 0 // no GC cycle before the fake statistics
+
+	read := func() map[string]*metricdata.Metric {
+		metrics := make(map[string]*metricdata.Metric)
+		for _, m := range p.Read() {
+			metrics[m.Descriptor.Name] = m
+		}
+		return metrics
+	}
+	read()
+	now = now.Add(time.Second)
+	metrics := read()
+	if reads != 1 {
+		t.Errorf("ReadMemStats called %d times within MinReadInterval; want 1", reads)
+	}
+
+	tests := []struct {
+		name string
+		typ  metricdata.Type
+		unit metricdata.Unit
+	}{
+		{"process/heap_alloc", metricdata.TypeGaugeInt64, metricdata.UnitBytes},
+		{"process/heap_inuse", metricdata.TypeGaugeInt64, metricdata.UnitBytes},
+		{"process/heap_objects", metricdata.TypeGaugeInt64, metricdata.UnitDimensionless},
+		{"process/gc_count", metricdata.TypeCumulativeInt64, metricdata.UnitDimensionless},
+		{"process/gc_pause", metricdata.TypeCumulativeDistribution, metricdata.UnitMilliseconds},
+		{"process/goroutines", metricdata.TypeGaugeInt64, metricdata.UnitDimensionless},
+		{"process/cgo_calls", metricdata.TypeCumulativeInt64, metricdata.UnitDimensionless},
+		{"process/gomaxprocs", metricdata.TypeGaugeInt64, metricdata.UnitDimensionless},
+	}
+	for _, tt := range tests {
+		m := metrics[tt.name]
+		if m == nil {
+			t.Errorf("%s: no metric", tt.name)
+			continue
+		}
+		if m.Descriptor.Type != tt.typ || m.Descriptor.Unit != tt.unit {
+			t.Errorf("%s: type, unit = %v, %q; want %v, %q", tt.name, m.Descriptor.Type, m.Descriptor.Unit, tt.typ, tt.unit)
+		}
+		ts := m.TimeSeries[0]
+		wantStart := p.start
+		if tt.typ == metricdata.TypeGaugeInt64 {
+			wantStart = now
+		}
+		if !ts.StartTime.Equal(wantStart) {
+			t.Errorf("%s: start time = %v; want %v", tt.name, ts.StartTime, wantStart)
+		}
+		if !ts.Points[0].Time.Equal(now) {
+			t.Errorf("%s: point time = %v; want %v", tt.name, ts.Points[0].Time, now)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	metrics = read()
+	if reads != 2 {
+		t.Errorf("ReadMemStats called %d times after MinReadInterval; want 2", reads)
+	}
+	if got := metrics["process/heap_alloc"].TimeSeries[0].Points[0].Value; got != int64(2048) {
+		t.Errorf("heap_alloc = %v; want 2048", got)
+	}
+	d := metrics["process/gc_pause"].TimeSeries[0].Points[0].Value.(*metricdata.Distribution)
+	if d.Count != 4 || d.Sum != 8 {
+		t.Errorf("gc_pause count, sum = %v, %v; want 4, 8", d.Count, d.Sum)
+	}
+}
+
+func TestProducer_PausesBeforeStart(t *testing.T) {
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	p := NewProducer(Options{})
+	var d *metricdata.Distribution
+	for _, m := range p.Read() {
+		if m.Descriptor.Name == "process/gc_pause" {
+			d = m.TimeSeries[0].Points[0].Value.(*metricdata.Distribution)
+		}
+	}
+	runtime.ReadMemStats(&after)
+	if max := int64(after.NumGC - before.NumGC); d.Count > max {
+		t.Errorf("gc_pause count = %d; want at most the %d GC cycles since NewProducer", d.Count, max)
+	}
+}
+
+func TestProducer_Prefix(t *testing.T) {
+	p := NewProducer(Options{Prefix: "myapp/"})
+	for _, m := range p.Read() {
+		if name := m.Descriptor.Name; !strings.HasPrefix(name, "myapp/") {
+			t.Errorf("metric name %q; want prefix myapp/", name)
+		}
+	}
+}
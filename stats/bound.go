@@ -0,0 +1,116 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package stats
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/exemplar"
+	"go.opencensus.io/stats/internal"
+	"go.opencensus.io/tag"
+)
+
+// bound records values of a measure with a fixed tag map.
+type bound struct {
+	desc   *measureDescriptor
+	tags   *tag.Map
+	record func(v float64, attachments map[string]string)
+}
+
+// bind binds m to the tags in ctx for recording to r, or to the default
+// recorder if r is nil.
+func bind(ctx context.Context, r Recorder, m Measure, desc *measureDescriptor) bound {
+	tags := tag.FromContext(ctx)
+	b := bound{desc: desc, tags: tags}
+	if r == nil {
+		if binder := internal.DefaultBinder; binder != nil {
+			b.record = binder(tags, m)
+		}
+		return b
+	}
+	if binder := internal.RecorderBinder; binder != nil {
+		b.record = binder(r, tags, m)
+	}
+	if b.record == nil {
+		// r does not support binding; record each value through it.
+		b.record = func(v float64, attachments map[string]string) {
+			r.Record(tags, []Measurement{{v: v, m: m, desc: desc}}, attachments, time.Time{})
+		}
+	}
+	return b
+}
+
+func (b *bound) add(ctx context.Context, v float64) {
+	if b.record == nil || !b.desc.subscribed() {
+		return
+	}
+	// The tag attachments are taken from the bound tags rather than from
+	// the tags in ctx, so that they match the recorded row.
+	b.record(v, exemplar.AttachmentsFromContext(tag.NewContext(ctx, b.tags)))
+}
+
+// BoundInt64Measure records values of an Int64Measure with the tags it was
+// bound to. Recording through it avoids resolving the tags for each
+// recorded value.
+type BoundInt64Measure struct {
+	b bound
+}
+
+// Bind returns a BoundInt64Measure recording values of m with the tags
+// in ctx.
+func (m *Int64Measure) Bind(ctx context.Context) *BoundInt64Measure {
+	return &BoundInt64Measure{bind(ctx, nil, m, m.desc)}
+}
+
+// BindTo is like Bind, but the returned BoundInt64Measure records to r
+// instead of the default recorder.
+func (m *Int64Measure) BindTo(ctx context.Context, r Recorder) *BoundInt64Measure {
+	return &BoundInt64Measure{bind(ctx, r, m, m.desc)}
+}
+
+// Record records v with the tags b was bound to. The tags in ctx are
+// ignored; the other exemplar attachments in ctx, such as its span
+// context, are recorded.
+func (b *BoundInt64Measure) Record(ctx context.Context, v int64) {
+	b.b.add(ctx, float64(v))
+}
+
+// BoundFloat64Measure records values of a Float64Measure with the tags it
+// was bound to. Recording through it avoids resolving the tags for each
+// recorded value.
+type BoundFloat64Measure struct {
+	b bound
+}
+
+// Bind returns a BoundFloat64Measure recording values of m with the tags
+// in ctx.
+func (m *Float64Measure) Bind(ctx context.Context) *BoundFloat64Measure {
+	return &BoundFloat64Measure{bind(ctx, nil, m, m.desc)}
+}
+
+// BindTo is like Bind, but the returned BoundFloat64Measure records to r
+// instead of the default recorder.
+func (m *Float64Measure) BindTo(ctx context.Context, r Recorder) *BoundFloat64Measure {
+	return &BoundFloat64Measure{bind(ctx, r, m, m.desc)}
+}
+
+// Record records v with the tags b was bound to. The tags in ctx are
+// ignored; the other exemplar attachments in ctx, such as its span
+// context, are recorded.
+func (b *BoundFloat64Measure) Record(ctx context.Context, v float64) {
+	b.b.add(ctx, v)
+}
@@ -49,6 +49,13 @@ Libraries can always record measurements, and applications can later decide
 on which measurements they want to collect by registering views. This allows
 libraries to turn on the instrumentation by default.
 
+Code recording the same measure with the same tags many times can bind the
+measure to the tags once with Bind, and record through the returned handle.
+BindTo binds to a Recorder such as a view.Meter.
+
+RecordWithOptions records measurements with explicit tag mutations, exemplar
+attachments and measurement time, for example to import historical data.
+
 Exemplars
 
 For a given recorded measurement, the associated exemplar is a diagnostic map
@@ -15,11 +15,23 @@
 package internal
 
 import (
+	"time"
+
 	"go.opencensus.io/tag"
 )
 
-// DefaultRecorder will be called for each Record call.
-var DefaultRecorder func(tags *tag.Map, measurement interface{}, attachments map[string]string)
+// DefaultRecorder will be called for each Record call. A zero t means the
+// measurements were made at the time of the call.
+var DefaultRecorder func(tags *tag.Map, measurement interface{}, attachments map[string]string, t time.Time)
 
 // SubscriptionReporter reports when a view subscribed with a measure.
 var SubscriptionReporter func(measure string)
+
+// DefaultBinder will be called to bind a measure to a tag map. It returns
+// the function recording the values of the bound measure.
+var DefaultBinder func(tags *tag.Map, measure interface{}) func(v float64, attachments map[string]string)
+
+// RecorderBinder will be called to bind a measure to a tag map for
+// recording to a stats.Recorder. It returns nil if the recorder does not
+// support binding.
+var RecorderBinder func(recorder interface{}, tags *tag.Map, measure interface{}) func(v float64, attachments map[string]string)
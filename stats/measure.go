@@ -16,6 +16,7 @@
 package stats
 
 import (
+	"sort"
 	"sync"
 	"sync/atomic"
 )
@@ -89,6 +90,30 @@ func registerMeasureHandle(name, desc, unit string) *measureDescriptor {
 	return m
 }
 
+// MeasureInfo describes a measure.
+type MeasureInfo struct {
+	Name        string
+	Description string
+	Unit        string
+}
+
+// RegisteredMeasures returns the measures created in the program, sorted
+// by name.
+func RegisteredMeasures() []MeasureInfo {
+	mu.RLock()
+	infos := make([]MeasureInfo, 0, len(measures))
+	for _, m := range measures {
+		infos = append(infos, MeasureInfo{
+			Name:        m.name,
+			Description: m.description,
+			Unit:        m.unit,
+		})
+	}
+	mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
 // Measurement is the numeric value measured when recording stats. Each measure
 // provides methods to create measurements of their kind. For example, Int64Measure
 // provides M to convert an int64 into a measurement.
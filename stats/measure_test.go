@@ -0,0 +1,42 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package stats
+
+import "testing"
+
+func TestRegisteredMeasures(t *testing.T) {
+	Int64("TestRegisteredMeasures/b", "desc b", UnitBytes)
+	Float64("TestRegisteredMeasures/a", "desc a", UnitMilliseconds)
+
+	var got []MeasureInfo
+	for _, m := range RegisteredMeasures() {
+		if m.Name == "TestRegisteredMeasures/a" || m.Name == "TestRegisteredMeasures/b" {
+			got = append(got, m)
+		}
+	}
+	want := []MeasureInfo{
+		{Name: "TestRegisteredMeasures/a", Description: "desc a", Unit: UnitMilliseconds},
+		{Name: "TestRegisteredMeasures/b", Description: "desc b", Unit: UnitBytes},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("RegisteredMeasures() = %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("RegisteredMeasures()[%d] = %v; want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -17,6 +17,7 @@ package stats
 
 import (
 	"context"
+	"time"
 
 	"go.opencensus.io/exemplar"
 	"go.opencensus.io/stats/internal"
@@ -31,6 +32,15 @@ func init() {
 	}
 }
 
+// Recorder records measurements. A view.Meter is a Recorder aggregating
+// the measurements in its own views.
+type Recorder interface {
+	// Record records the measurements, of type []Measurement, made at t
+	// with the given tags and exemplar attachments. A zero t means the
+	// measurements were made at the time of the call.
+	Record(tags *tag.Map, measurements interface{}, attachments map[string]string, t time.Time)
+}
+
 // Record records one or multiple measurements with the same context at once.
 // If there are any tags in the context, measurements will be tagged with them.
 func Record(ctx context.Context, ms ...Measurement) {
@@ -38,20 +48,120 @@ func Record(ctx context.Context, ms ...Measurement) {
 	if recorder == nil {
 		return
 	}
-	if len(ms) == 0 {
+	if !subscribed(ms) {
+		return
+	}
+	recorder(tag.FromContext(ctx), ms, exemplar.AttachmentsFromContext(ctx), time.Time{})
+}
+
+// RecordTo is like Record, but records the measurements to r instead of
+// the default recorder.
+func RecordTo(ctx context.Context, r Recorder, ms ...Measurement) {
+	if !subscribed(ms) {
 		return
 	}
-	record := false
+	r.Record(tag.FromContext(ctx), ms, exemplar.AttachmentsFromContext(ctx), time.Time{})
+}
+
+// subscribed returns true if any of the measurements is of a measure
+// used by a registered view.
+func subscribed(ms []Measurement) bool {
 	for _, m := range ms {
 		if m.desc.subscribed() {
-			record = true
-			break
+			return true
 		}
 	}
-	if !record {
-		return
+	return false
+}
+
+type recordOptions struct {
+	mutators     []tag.Mutator
+	attachments  map[string]string
+	measurements []Measurement
+	recorder     Recorder
+	t            time.Time
+}
+
+// Options apply changes to the way RecordWithOptions records measurements.
+type Options func(*recordOptions)
+
+// WithTags applies the mutators to the tags in the context of the recorded
+// measurements. The context itself is not modified.
+func WithTags(mutators ...tag.Mutator) Options {
+	return func(o *recordOptions) {
+		o.mutators = append(o.mutators, mutators...)
+	}
+}
+
+// WithAttachments adds exemplar attachments to the ones extracted from the
+// context. On conflict, the given attachments take precedence.
+func WithAttachments(attachments map[string]string) Options {
+	return func(o *recordOptions) {
+		if o.attachments == nil {
+			o.attachments = make(map[string]string, len(attachments))
+		}
+		for k, v := range attachments {
+			o.attachments[k] = v
+		}
+	}
+}
+
+// WithMeasurements adds measurements to record.
+func WithMeasurements(measurements ...Measurement) Options {
+	return func(o *recordOptions) {
+		o.measurements = append(o.measurements, measurements...)
+	}
+}
+
+// WithRecorder records the measurements to r instead of the default
+// recorder.
+func WithRecorder(r Recorder) Options {
+	return func(o *recordOptions) {
+		o.recorder = r
+	}
+}
+
+// WithTime records the measurements as made at t instead of the time of
+// the call. It is used as the time of the exemplars of the measurements.
+func WithTime(t time.Time) Options {
+	return func(o *recordOptions) {
+		o.t = t
+	}
+}
+
+// RecordWithOptions records measurements with the given options. With no
+// options other than WithMeasurements, it is equivalent to Record.
+func RecordWithOptions(ctx context.Context, ros ...Options) error {
+	var o recordOptions
+	for _, ro := range ros {
+		ro(&o)
+	}
+	if !subscribed(o.measurements) {
+		return nil
+	}
+	if len(o.mutators) > 0 {
+		var err error
+		if ctx, err = tag.New(ctx, o.mutators...); err != nil {
+			return err
+		}
+	}
+	attachments := exemplar.AttachmentsFromContext(ctx)
+	if len(o.attachments) > 0 {
+		if attachments == nil {
+			attachments = make(map[string]string, len(o.attachments))
+		}
+		for k, v := range o.attachments {
+			attachments[k] = v
+		}
+	}
+	if o.recorder != nil {
+		o.recorder.Record(tag.FromContext(ctx), o.measurements, attachments, o.t)
+		return nil
 	}
-	recorder(tag.FromContext(ctx), ms, exemplar.AttachmentsFromContext(ctx))
+	if recorder := internal.DefaultRecorder; recorder != nil {
+		recorder(tag.FromContext(ctx), o.measurements, attachments, o.t)
+	}
+	return nil
 }
 
 // RecordWithTags records one or multiple measurements at once.
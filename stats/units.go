@@ -15,6 +15,11 @@
 
 package stats
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Units are encoded according to the case-sensitive abbreviations from the
 // Unified Code for Units of Measure: http://unitsofmeasure.org/ucum.html
 const (
@@ -23,3 +28,142 @@ const (
 	UnitBytes         = "By"
 	UnitMilliseconds  = "ms"
 )
+
+// Unit is a unit parsed by ParseUnit: an atom with an optional prefix and
+// annotation, optionally divided by another unit.
+type Unit struct {
+	Prefix     string // Prefix is the metric or binary prefix of Atom, such as "m" or "Ki", if any.
+	Atom       string // Atom is the unit atom, such as "s", "By" or "1".
+	Annotation string // Annotation is the text in curly braces, such as "request" for "{request}".
+	Per        *Unit  // Per is the unit dividing this one, such as "s" for "By/s", if any.
+}
+
+type unitAtom struct {
+	base   string  // base is the atom the atom is converted to.
+	scale  float64 // scale converts values in the atom to base.
+	metric bool    // metric is true if the atom takes prefixes.
+}
+
+var unitAtoms = map[string]unitAtom{
+	"1":   {"1", 1, false},
+	"%":   {"1", 0.01, false},
+	"s":   {"s", 1, true},
+	"min": {"s", 60, false},
+	"h":   {"s", 60 * 60, false},
+	"d":   {"s", 24 * 60 * 60, false},
+	"By":  {"By", 1, true},
+	"bit": {"By", 0.125, true},
+	"m":   {"m", 1, true},
+	"g":   {"g", 1, true},
+	"Hz":  {"Hz", 1, true},
+	"V":   {"V", 1, true},
+	"A":   {"A", 1, true},
+	"J":   {"J", 1, true},
+	"W":   {"W", 1, true},
+	"K":   {"K", 1, true},
+}
+
+var unitPrefixes = map[string]float64{
+	"Y": 1e24, "Z": 1e21, "E": 1e18, "P": 1e15, "T": 1e12, "G": 1e9,
+	"M": 1e6, "k": 1e3, "h": 1e2, "da": 1e1, "d": 1e-1, "c": 1e-2,
+	"m": 1e-3, "u": 1e-6, "n": 1e-9, "p": 1e-12, "f": 1e-15, "a": 1e-18,
+	"z": 1e-21, "y": 1e-24,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// ParseUnit parses a unit written with the case-sensitive UCUM
+// abbreviations, such as "ms", "KiBy", "By/s" or "{request}". It supports
+// the units of time, information, length, mass, frequency, voltage,
+// current, energy, power and temperature, with metric and binary prefixes.
+// Annotations alone stand for the unit "1".
+func ParseUnit(s string) (Unit, error) {
+	num, den := s, ""
+	i := strings.IndexByte(s, '/')
+	if i >= 0 {
+		num, den = s[:i], s[i+1:]
+	}
+	u, err := parseUnitTerm(num)
+	if err != nil {
+		return Unit{}, fmt.Errorf("invalid unit %q: %v", s, err)
+	}
+	if i >= 0 {
+		per, err := parseUnitTerm(den)
+		if err != nil {
+			return Unit{}, fmt.Errorf("invalid unit %q: %v", s, err)
+		}
+		u.Per = &per
+	}
+	return u, nil
+}
+
+func parseUnitTerm(s string) (Unit, error) {
+	var u Unit
+	if i := strings.IndexByte(s, '{'); i >= 0 {
+		if !strings.HasSuffix(s, "}") || strings.ContainsAny(s[i+1:len(s)-1], "{}") {
+			return Unit{}, fmt.Errorf("malformed annotation in %q", s)
+		}
+		u.Annotation = s[i+1 : len(s)-1]
+		s = s[:i]
+		if s == "" {
+			u.Atom = "1"
+			return u, nil
+		}
+	}
+	if _, ok := unitAtoms[s]; ok {
+		u.Atom = s
+		return u, nil
+	}
+	for n := 2; n > 0; n-- {
+		if len(s) <= n {
+			continue
+		}
+		if _, ok := unitPrefixes[s[:n]]; !ok {
+			continue
+		}
+		if a, ok := unitAtoms[s[n:]]; ok && a.metric {
+			u.Prefix, u.Atom = s[:n], s[n:]
+			return u, nil
+		}
+	}
+	return Unit{}, fmt.Errorf("unknown unit %q", s)
+}
+
+// base returns the base unit of u and the factor converting values in u
+// to it.
+func (u Unit) base() (string, float64) {
+	a := unitAtoms[u.Atom]
+	base, scale := a.base, a.scale
+	if u.Prefix != "" {
+		scale *= unitPrefixes[u.Prefix]
+	}
+	if u.Per != nil {
+		perBase, perScale := u.Per.base()
+		base += "/" + perBase
+		scale /= perScale
+	}
+	return base, scale
+}
+
+// UnitConversion returns the factor converting values in the unit from to
+// the unit to, such as 1e-6 from "ns" to "ms". It returns an error if
+// either unit cannot be parsed by ParseUnit or if they measure different
+// quantities.
+func UnitConversion(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	f, err := ParseUnit(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := ParseUnit(to)
+	if err != nil {
+		return 0, err
+	}
+	fromBase, fromScale := f.base()
+	toBase, toScale := t.base()
+	if fromBase != toBase {
+		return 0, fmt.Errorf("cannot convert unit %q to %q", from, to)
+	}
+	return fromScale / toScale, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		unit    string
+		want    Unit
+		wantErr bool
+	}{
+		{unit: "1", want: Unit{Atom: "1"}},
+		{unit: "ms", want: Unit{Prefix: "m", Atom: "s"}},
+		{unit: "min", want: Unit{Atom: "min"}},
+		{unit: "KiBy", want: Unit{Prefix: "Ki", Atom: "By"}},
+		{unit: "dam", want: Unit{Prefix: "da", Atom: "m"}},
+		{unit: "By/s", want: Unit{Atom: "By", Per: &Unit{Atom: "s"}}},
+		{unit: "{request}", want: Unit{Atom: "1", Annotation: "request"}},
+		{unit: "By{body}", want: Unit{Atom: "By", Annotation: "body"}},
+		{unit: "", wantErr: true},
+		{unit: "kmin", wantErr: true},
+		{unit: "parsecs", wantErr: true},
+		{unit: "{request", wantErr: true},
+		{unit: "By/", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnit(tt.unit)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseUnit(%q) error = %v; want error %v", tt.unit, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseUnit(%q) = %+v; want %+v", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestUnitConversion(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{from: "ns", to: "ms", want: 1e-6},
+		{from: "ms", to: "s", want: 1e-3},
+		{from: "h", to: "s", want: 3600},
+		{from: "KiBy", to: "By", want: 1024},
+		{from: "bit", to: "By", want: 0.125},
+		{from: "MBy/s", to: "kBy/s", want: 1000},
+		{from: "%", to: "1", want: 0.01},
+		{from: "{request}", to: "1", want: 1},
+		{from: "custom", to: "custom", want: 1},
+		{from: "s", to: "By", wantErr: true},
+		{from: "By/s", to: "By", wantErr: true},
+		{from: "custom", to: "s", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := UnitConversion(tt.from, tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnitConversion(%q, %q) error = %v; want error %v", tt.from, tt.to, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && math.Abs(got-tt.want) > 1e-12*tt.want {
+			t.Errorf("UnitConversion(%q, %q) = %v; want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -15,6 +15,8 @@
 
 package view
 
+import "time"
+
 // AggType represents the type of aggregation function used on a View.
 type AggType int
 
@@ -25,6 +27,8 @@ const (
 	AggTypeSum                         // the sum aggregation, see Sum.
 	AggTypeDistribution                // the distribution aggregation, see Distribution.
 	AggTypeLastValue                   // the last value aggregation, see LastValue.
+	AggTypeQuantiles                   // the quantile aggregation, see Quantiles.
+	AggTypeExponential                 // the exponential distribution aggregation, see ExponentialDistribution.
 )
 
 func (t AggType) String() string {
@@ -37,14 +41,23 @@ var aggTypeName = map[AggType]string{
 	AggTypeSum:          "Sum",
 	AggTypeDistribution: "Distribution",
 	AggTypeLastValue:    "LastValue",
+	AggTypeQuantiles:    "Quantiles",
+	AggTypeExponential:  "Exponential",
 }
 
 // Aggregation represents a data aggregation method. Use one of the functions:
-// Count, Sum, or Distribution to construct an Aggregation.
+// Count, Sum, Distribution, LastValue, Quantiles or ExponentialDistribution
+// to construct an Aggregation.
 type Aggregation struct {
 	Type    AggType   // Type is the AggType of this Aggregation.
 	Buckets []float64 // Buckets are the bucket endpoints if this Aggregation represents a distribution, see Distribution.
 
+	Quantiles []float64     // Quantiles are the estimated quantiles if this Aggregation represents quantiles, see Quantiles.
+	MaxAge    time.Duration // MaxAge is the duration of the sliding window quantiles are estimated over.
+
+	MaxScale   int32 // MaxScale is the initial scale if this Aggregation represents an exponential distribution, see ExponentialDistribution.
+	MaxBuckets int   // MaxBuckets is the maximum number of buckets if this Aggregation represents an exponential distribution.
+
 	newData func() AggregationData
 }
 
@@ -118,3 +131,72 @@ func LastValue() *Aggregation {
 		},
 	}
 }
+
+// DefaultQuantileMaxAge is the duration of the sliding window used by the
+// aggregations returned by Quantiles.
+const DefaultQuantileMaxAge = 10 * time.Minute
+
+// Quantiles indicates that the desired aggregation is a summary of the
+// recorded values: their count and sum, and an estimate of the given
+// quantiles over the values recorded during the last DefaultQuantileMaxAge.
+// Quantiles must be in the range (0, 1]; for example, 0.99 is the 99th
+// percentile.
+//
+// The quantiles are estimated with the CKMS algorithm, with an error of
+// 10% of the distance of each quantile to 0 or 1, whichever is closer.
+//
+// Exporters that receive Delta data get the count and sum of the values
+// recorded during each reporting interval, and the same quantiles.
+func Quantiles(quantiles ...float64) *Aggregation {
+	return QuantilesWithMaxAge(DefaultQuantileMaxAge, quantiles...)
+}
+
+// QuantilesWithMaxAge is like Quantiles, but estimates the quantiles over
+// the values recorded during the last maxAge.
+func QuantilesWithMaxAge(maxAge time.Duration, quantiles ...float64) *Aggregation {
+	return &Aggregation{
+		Type:      AggTypeQuantiles,
+		Quantiles: quantiles,
+		MaxAge:    maxAge,
+		newData: func() AggregationData {
+			return newQuantileData(quantiles, maxAge)
+		},
+	}
+}
+
+// Limits of the scale of exponential distributions.
+const (
+	MinExponentialScale = -10
+	MaxExponentialScale = 20
+)
+
+// ExponentialDistribution indicates that the desired aggregation is a
+// histogram distribution with base-2 exponential buckets, whose range is
+// picked from the recorded values.
+//
+// At a given scale, the bucket boundaries are the integer powers of
+// base = 2^(2^-scale), and bucket i holds the values in [base^i, base^(i+1)).
+// Each increment of the scale halves the width of the buckets on a
+// logarithmic scale.
+//
+// The aggregation starts at maxScale. When the recorded positive values
+// would need more than maxBuckets buckets, the scale is decreased, merging
+// adjacent buckets, until they fit. Values less than or equal to zero are
+// counted in a separate zero bucket.
+//
+// maxScale must be in the range [MinExponentialScale, MaxExponentialScale]
+// and maxBuckets must be at least 2. A maxScale of 20 and maxBuckets of 160
+// cover values from 1 to 2^20 with a relative error below 5%.
+//
+// The aggregated ExponentialData can be converted to DistributionData for
+// exporters that need explicit bucket bounds.
+func ExponentialDistribution(maxScale int32, maxBuckets int) *Aggregation {
+	return &Aggregation{
+		Type:       AggTypeExponential,
+		MaxScale:   maxScale,
+		MaxBuckets: maxBuckets,
+		newData: func() AggregationData {
+			return newExponentialData(maxScale, maxBuckets)
+		},
+	}
+}
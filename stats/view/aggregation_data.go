@@ -17,6 +17,7 @@ package view
 
 import (
 	"math"
+	"time"
 
 	"go.opencensus.io/exemplar"
 )
@@ -29,6 +30,9 @@ type AggregationData interface {
 	addSample(e *exemplar.Exemplar)
 	clone() AggregationData
 	equal(other AggregationData) bool
+	// sub returns the data aggregated since prev, an earlier snapshot of
+	// the same row, or nil if no sample was aggregated since.
+	sub(prev AggregationData) AggregationData
 }
 
 const epsilon = 1e-9
@@ -51,6 +55,14 @@ func (a *CountData) clone() AggregationData {
 	return &CountData{Value: a.Value}
 }
 
+func (a *CountData) sub(prev AggregationData) AggregationData {
+	n := a.Value - prev.(*CountData).Value
+	if n == 0 {
+		return nil
+	}
+	return &CountData{Value: n}
+}
+
 func (a *CountData) equal(other AggregationData) bool {
 	a2, ok := other.(*CountData)
 	if !ok {
@@ -66,16 +78,38 @@ func (a *CountData) equal(other AggregationData) bool {
 // Most users won't directly access sum data.
 type SumData struct {
 	Value float64
+	// Exemplars are the exemplars retained by the ExemplarPolicy of the
+	// view, if any.
+	Exemplars []*exemplar.Exemplar
+	policy    exemplar.Policy
+	count     int64
 }
 
 func (a *SumData) isAggregationData() bool { return true }
 
 func (a *SumData) addSample(e *exemplar.Exemplar) {
 	a.Value += e.Value
+	a.count++
+	if a.policy != nil {
+		a.Exemplars = a.policy.Offer(a.Exemplars, e, a.count)
+	}
 }
 
 func (a *SumData) clone() AggregationData {
-	return &SumData{Value: a.Value}
+	c := *a
+	return &c
+}
+
+func (a *SumData) sub(prev AggregationData) AggregationData {
+	p := prev.(*SumData)
+	if a.count == p.count {
+		return nil
+	}
+	c := *a
+	c.Value -= p.Value
+	c.count -= p.count
+	c.Exemplars = newExemplars(a.Exemplars, p.Exemplars)
+	return &c
 }
 
 func (a *SumData) equal(other AggregationData) bool {
@@ -103,7 +137,13 @@ type DistributionData struct {
 	// ExemplarsPerBucket is slice the same length as CountPerBucket containing
 	// an exemplar for the associated bucket, or nil.
 	ExemplarsPerBucket []*exemplar.Exemplar
-	bounds             []float64 // histogram distribution of the values
+	// BucketExemplars is nil unless the view sets an ExemplarPolicy. It is
+	// then a slice the same length as CountPerBucket containing all the
+	// exemplars the policy retains for the associated bucket, the last of
+	// which is also in ExemplarsPerBucket.
+	BucketExemplars [][]*exemplar.Exemplar
+	bounds          []float64 // histogram distribution of the values
+	policy          exemplar.Policy
 }
 
 func newDistributionData(bounds []float64) *DistributionData {
@@ -151,21 +191,27 @@ func (a *DistributionData) addSample(e *exemplar.Exemplar) {
 }
 
 func (a *DistributionData) addToBucket(e *exemplar.Exemplar) {
-	var count *int64
-	var ex **exemplar.Exemplar
-	for i, b := range a.bounds {
+	i := len(a.bounds)
+	for j, b := range a.bounds {
 		if e.Value < b {
-			count = &a.CountPerBucket[i]
-			ex = &a.ExemplarsPerBucket[i]
+			i = j
 			break
 		}
 	}
-	if count == nil {
-		count = &a.CountPerBucket[len(a.bounds)]
-		ex = &a.ExemplarsPerBucket[len(a.bounds)]
+	a.CountPerBucket[i]++
+	if a.policy == nil {
+		a.ExemplarsPerBucket[i] = maybeRetainExemplar(a.ExemplarsPerBucket[i], e)
+		return
+	}
+	if a.BucketExemplars == nil {
+		a.BucketExemplars = make([][]*exemplar.Exemplar, len(a.CountPerBucket))
+	}
+	held := a.policy.Offer(a.BucketExemplars[i], e, a.CountPerBucket[i])
+	a.BucketExemplars[i] = held
+	a.ExemplarsPerBucket[i] = nil
+	if len(held) > 0 {
+		a.ExemplarsPerBucket[i] = held[len(held)-1]
 	}
-	*count++
-	*ex = maybeRetainExemplar(*ex, e)
 }
 
 func maybeRetainExemplar(old, cur *exemplar.Exemplar) *exemplar.Exemplar {
@@ -187,9 +233,72 @@ func (a *DistributionData) clone() AggregationData {
 	c := *a
 	c.CountPerBucket = append([]int64(nil), a.CountPerBucket...)
 	c.ExemplarsPerBucket = append([]*exemplar.Exemplar(nil), a.ExemplarsPerBucket...)
+	if a.BucketExemplars != nil {
+		c.BucketExemplars = append([][]*exemplar.Exemplar(nil), a.BucketExemplars...)
+	}
 	return &c
 }
 
+// sub returns the distribution of the samples aggregated since prev. Its
+// Min and Max are those of all the samples of a.
+func (a *DistributionData) sub(prev AggregationData) AggregationData {
+	p := prev.(*DistributionData)
+	if a.Count == p.Count {
+		return nil
+	}
+	c := a.clone().(*DistributionData)
+	c.Count -= p.Count
+	c.Mean, c.SumOfSquaredDev = subMoments(a.Count, a.Mean, a.SumOfSquaredDev, p.Count, p.Mean, p.SumOfSquaredDev)
+	for i := range c.CountPerBucket {
+		c.CountPerBucket[i] -= p.CountPerBucket[i]
+		if c.ExemplarsPerBucket[i] == p.ExemplarsPerBucket[i] {
+			c.ExemplarsPerBucket[i] = nil
+		}
+		if c.BucketExemplars != nil && p.BucketExemplars != nil {
+			c.BucketExemplars[i] = newExemplars(c.BucketExemplars[i], p.BucketExemplars[i])
+		}
+	}
+	return c
+}
+
+// subMoments returns the mean and the sum of squared deviations of the
+// samples of a set of n samples that are not in a subset of pn samples.
+func subMoments(n int64, mean, ssd float64, pn int64, pmean, pssd float64) (float64, float64) {
+	if pn == 0 {
+		return mean, ssd
+	}
+	dn := n - pn
+	dmean := (mean*float64(n) - pmean*float64(pn)) / float64(dn)
+	d := dmean - pmean
+	dssd := ssd - pssd - d*d*float64(pn)*float64(dn)/float64(n)
+	if dssd < 0 {
+		// Rounding errors.
+		dssd = 0
+	}
+	return dmean, dssd
+}
+
+// newExemplars returns the exemplars of held that are not in prev.
+func newExemplars(held, prev []*exemplar.Exemplar) []*exemplar.Exemplar {
+	if len(prev) == 0 {
+		return held
+	}
+	var exemplars []*exemplar.Exemplar
+	for _, e := range held {
+		old := false
+		for _, pe := range prev {
+			if e == pe {
+				old = true
+				break
+			}
+		}
+		if !old {
+			exemplars = append(exemplars, e)
+		}
+	}
+	return exemplars
+}
+
 func (a *DistributionData) equal(other AggregationData) bool {
 	a2, ok := other.(*DistributionData)
 	if !ok {
@@ -212,6 +321,11 @@ func (a *DistributionData) equal(other AggregationData) bool {
 // LastValueData returns the last value recorded for LastValue aggregation.
 type LastValueData struct {
 	Value float64
+	// Exemplars are the exemplars retained by the ExemplarPolicy of the
+	// view, if any.
+	Exemplars []*exemplar.Exemplar
+	policy    exemplar.Policy
+	count     int64
 }
 
 func (l *LastValueData) isAggregationData() bool {
@@ -220,10 +334,20 @@ func (l *LastValueData) isAggregationData() bool {
 
 func (l *LastValueData) addSample(e *exemplar.Exemplar) {
 	l.Value = e.Value
+	if l.policy != nil {
+		l.count++
+		l.Exemplars = l.policy.Offer(l.Exemplars, e, l.count)
+	}
 }
 
 func (l *LastValueData) clone() AggregationData {
-	return &LastValueData{l.Value}
+	c := *l
+	return &c
+}
+
+// sub returns the last value, since it is a gauge.
+func (l *LastValueData) sub(AggregationData) AggregationData {
+	return l.clone()
 }
 
 func (l *LastValueData) equal(other AggregationData) bool {
@@ -233,3 +357,312 @@ func (l *LastValueData) equal(other AggregationData) bool {
 	}
 	return l.Value == a2.Value
 }
+
+// QuantileData is the aggregated data for the Quantiles aggregation.
+//
+// Most users won't directly access quantile data.
+type QuantileData struct {
+	Count int64   // number of data points aggregated
+	Sum   float64 // sum of the data points aggregated
+	// Quantiles are the quantiles of the aggregation, in ascending order.
+	Quantiles []float64
+	// Values holds the estimated value of each of the Quantiles over the data
+	// points recorded in the aggregation's sliding window, or NaN if there
+	// are none.
+	Values []float64
+	// WindowCount and WindowSum are the number and sum of the data points
+	// recorded in the sliding window.
+	WindowCount int64
+	WindowSum   float64
+
+	window *quantileWindow
+}
+
+func newQuantileData(quantiles []float64, maxAge time.Duration) *QuantileData {
+	return &QuantileData{
+		Quantiles: quantiles,
+		window:    newQuantileWindow(quantiles, maxAge),
+	}
+}
+
+func (a *QuantileData) isAggregationData() bool { return true }
+
+func (a *QuantileData) addSample(e *exemplar.Exemplar) {
+	a.Count++
+	a.Sum += e.Value
+	// The window ages samples from the time they are aggregated, so that
+	// samples recorded with a past time are not dropped right away.
+	a.window.add(time.Now(), e.Value)
+}
+
+// clone returns a snapshot of a with the quantiles estimated as of now.
+// The snapshot does not aggregate further samples.
+func (a *QuantileData) clone() AggregationData {
+	c := *a
+	c.Quantiles = append([]float64(nil), a.Quantiles...)
+	c.Values = append([]float64(nil), a.Values...)
+	c.window = nil
+	if a.window != nil {
+		c.Values, c.WindowCount, c.WindowSum = a.window.snapshot(time.Now())
+	}
+	return &c
+}
+
+// sub returns the count and sum of the samples aggregated since prev, and
+// the quantiles of the current window.
+func (a *QuantileData) sub(prev AggregationData) AggregationData {
+	p := prev.(*QuantileData)
+	if a.Count == p.Count {
+		return nil
+	}
+	c := a.clone().(*QuantileData)
+	c.Count -= p.Count
+	c.Sum -= p.Sum
+	return c
+}
+
+func (a *QuantileData) equal(other AggregationData) bool {
+	a2, ok := other.(*QuantileData)
+	if !ok {
+		return false
+	}
+	if len(a.Values) != len(a2.Values) || len(a.Quantiles) != len(a2.Quantiles) {
+		return false
+	}
+	for i := range a.Quantiles {
+		if a.Quantiles[i] != a2.Quantiles[i] {
+			return false
+		}
+	}
+	for i := range a.Values {
+		if !(math.IsNaN(a.Values[i]) && math.IsNaN(a2.Values[i])) && math.Pow(a.Values[i]-a2.Values[i], 2) >= epsilon {
+			return false
+		}
+	}
+	return a.Count == a2.Count && math.Pow(a.Sum-a2.Sum, 2) < epsilon &&
+		a.WindowCount == a2.WindowCount && math.Pow(a.WindowSum-a2.WindowSum, 2) < epsilon
+}
+
+// ExponentialData is the aggregated data for the ExponentialDistribution
+// aggregation.
+//
+// Bucket Offset+i of CountPerBucket holds the values in
+// [base^(Offset+i), base^(Offset+i+1)), where base = 2^(2^-Scale).
+//
+// Most users won't directly access exponential distribution data.
+type ExponentialData struct {
+	Count           int64   // number of data points aggregated
+	Min             float64 // minimum value in the distribution
+	Max             float64 // max value in the distribution
+	Mean            float64 // mean of the distribution
+	SumOfSquaredDev float64 // sum of the squared deviation from the mean
+	Scale           int32   // scale of the buckets
+	ZeroCount       int64   // number of values less than or equal to zero
+	Offset          int64   // index of the first bucket of CountPerBucket
+	CountPerBucket  []int64 // number of occurrences per bucket
+	// ZeroExemplar is an exemplar for the zero bucket, or nil.
+	ZeroExemplar *exemplar.Exemplar
+	// ExemplarsPerBucket is slice the same length as CountPerBucket containing
+	// an exemplar for the associated bucket, or nil.
+	ExemplarsPerBucket []*exemplar.Exemplar
+	maxBuckets         int
+}
+
+func newExponentialData(scale int32, maxBuckets int) *ExponentialData {
+	return &ExponentialData{
+		Scale:      scale,
+		maxBuckets: maxBuckets,
+		Min:        math.MaxFloat64,
+		Max:        math.SmallestNonzeroFloat64,
+	}
+}
+
+// Sum returns the sum of all samples collected.
+func (a *ExponentialData) Sum() float64 { return a.Mean * float64(a.Count) }
+
+func (a *ExponentialData) isAggregationData() bool { return true }
+
+func (a *ExponentialData) addSample(e *exemplar.Exemplar) {
+	f := e.Value
+	if f < a.Min {
+		a.Min = f
+	}
+	if f > a.Max {
+		a.Max = f
+	}
+	a.Count++
+	a.addToBucket(e)
+
+	if a.Count == 1 {
+		a.Mean = f
+		return
+	}
+
+	oldMean := a.Mean
+	a.Mean = a.Mean + (f-a.Mean)/float64(a.Count)
+	a.SumOfSquaredDev = a.SumOfSquaredDev + (f-oldMean)*(f-a.Mean)
+}
+
+func (a *ExponentialData) addToBucket(e *exemplar.Exemplar) {
+	if e.Value <= 0 {
+		a.ZeroCount++
+		a.ZeroExemplar = maybeRetainExemplar(a.ZeroExemplar, e)
+		return
+	}
+	idx := exponentialIndex(e.Value, a.Scale)
+	if len(a.CountPerBucket) == 0 {
+		a.Offset = idx
+		a.CountPerBucket = make([]int64, 1)
+		a.ExemplarsPerBucket = make([]*exemplar.Exemplar, 1)
+	}
+	lo, hi := a.Offset, a.Offset+int64(len(a.CountPerBucket))-1
+	if idx < lo {
+		lo = idx
+	}
+	if idx > hi {
+		hi = idx
+	}
+	var change uint
+	for (hi>>change)-(lo>>change)+1 > int64(a.maxBuckets) {
+		change++
+	}
+	if change > 0 {
+		a.downscale(change)
+		idx >>= change
+	}
+	if idx < a.Offset {
+		n := a.Offset - idx
+		a.CountPerBucket = append(make([]int64, n), a.CountPerBucket...)
+		a.ExemplarsPerBucket = append(make([]*exemplar.Exemplar, n), a.ExemplarsPerBucket...)
+		a.Offset = idx
+	}
+	for idx >= a.Offset+int64(len(a.CountPerBucket)) {
+		a.CountPerBucket = append(a.CountPerBucket, 0)
+		a.ExemplarsPerBucket = append(a.ExemplarsPerBucket, nil)
+	}
+	i := idx - a.Offset
+	a.CountPerBucket[i]++
+	a.ExemplarsPerBucket[i] = maybeRetainExemplar(a.ExemplarsPerBucket[i], e)
+}
+
+// downscale decreases the scale by change, merging each group of 2^change
+// adjacent buckets.
+func (a *ExponentialData) downscale(change uint) {
+	offset := a.Offset >> change
+	n := (a.Offset+int64(len(a.CountPerBucket))-1)>>change - offset + 1
+	counts := make([]int64, n)
+	exemplars := make([]*exemplar.Exemplar, n)
+	for i, c := range a.CountPerBucket {
+		j := (a.Offset+int64(i))>>change - offset
+		counts[j] += c
+		if ex := a.ExemplarsPerBucket[i]; ex != nil {
+			exemplars[j] = maybeRetainExemplar(exemplars[j], ex)
+		}
+	}
+	a.Scale -= int32(change)
+	a.Offset = offset
+	a.CountPerBucket = counts
+	a.ExemplarsPerBucket = exemplars
+}
+
+// Bounds returns the explicit bucket bounds equivalent to the buckets of a:
+// the lower bound of each bucket followed by the upper bound of the last
+// one. It returns nil if no positive values were recorded.
+func (a *ExponentialData) Bounds() []float64 {
+	if len(a.CountPerBucket) == 0 {
+		return nil
+	}
+	bounds := make([]float64, len(a.CountPerBucket)+1)
+	for i := range bounds {
+		bounds[i] = exponentialLowerBound(a.Offset+int64(i), a.Scale)
+	}
+	return bounds
+}
+
+// DistributionData returns the DistributionData equivalent to a, with the
+// bucket bounds returned by Bounds. Its first bucket holds the values less
+// than or equal to zero and its last bucket is empty.
+func (a *ExponentialData) DistributionData() *DistributionData {
+	d := newDistributionData(a.Bounds())
+	d.Count = a.Count
+	d.Min = a.Min
+	d.Max = a.Max
+	d.Mean = a.Mean
+	d.SumOfSquaredDev = a.SumOfSquaredDev
+	d.CountPerBucket[0] = a.ZeroCount
+	d.ExemplarsPerBucket[0] = a.ZeroExemplar
+	copy(d.CountPerBucket[1:], a.CountPerBucket)
+	copy(d.ExemplarsPerBucket[1:], a.ExemplarsPerBucket)
+	return d
+}
+
+func (a *ExponentialData) clone() AggregationData {
+	c := *a
+	c.CountPerBucket = append([]int64(nil), a.CountPerBucket...)
+	c.ExemplarsPerBucket = append([]*exemplar.Exemplar(nil), a.ExemplarsPerBucket...)
+	return &c
+}
+
+// sub returns the distribution of the samples aggregated since prev, at the
+// scale of a. Its Min and Max are those of all the samples of a.
+func (a *ExponentialData) sub(prev AggregationData) AggregationData {
+	p := prev.(*ExponentialData)
+	if a.Count == p.Count {
+		return nil
+	}
+	if p.Scale > a.Scale {
+		p = p.clone().(*ExponentialData)
+		p.downscale(uint(p.Scale - a.Scale))
+	}
+	c := a.clone().(*ExponentialData)
+	c.Count -= p.Count
+	c.Mean, c.SumOfSquaredDev = subMoments(a.Count, a.Mean, a.SumOfSquaredDev, p.Count, p.Mean, p.SumOfSquaredDev)
+	c.ZeroCount -= p.ZeroCount
+	if c.ZeroExemplar == p.ZeroExemplar {
+		c.ZeroExemplar = nil
+	}
+	for j, n := range p.CountPerBucket {
+		i := p.Offset + int64(j) - c.Offset
+		c.CountPerBucket[i] -= n
+		if c.ExemplarsPerBucket[i] == p.ExemplarsPerBucket[j] {
+			c.ExemplarsPerBucket[i] = nil
+		}
+	}
+	return c
+}
+
+func (a *ExponentialData) equal(other AggregationData) bool {
+	a2, ok := other.(*ExponentialData)
+	if !ok || a2 == nil {
+		return false
+	}
+	if a.Scale != a2.Scale || a.Offset != a2.Offset || a.ZeroCount != a2.ZeroCount || len(a.CountPerBucket) != len(a2.CountPerBucket) {
+		return false
+	}
+	for i := range a.CountPerBucket {
+		if a.CountPerBucket[i] != a2.CountPerBucket[i] {
+			return false
+		}
+	}
+	return a.Count == a2.Count && a.Min == a2.Min && a.Max == a2.Max && math.Pow(a.Mean-a2.Mean, 2) < epsilon && math.Pow(a.SumOfSquaredDev-a2.SumOfSquaredDev, 2) < epsilon
+}
+
+// exponentialIndex returns the index of the bucket holding v > 0 at the
+// given scale.
+func exponentialIndex(v float64, scale int32) int64 {
+	i := int64(math.Floor(math.Log2(v) * math.Exp2(float64(scale))))
+	// Correct rounding errors so that the index is consistent with the
+	// bounds returned by exponentialLowerBound.
+	if v < exponentialLowerBound(i, scale) {
+		i--
+	} else if v >= exponentialLowerBound(i+1, scale) {
+		i++
+	}
+	return i
+}
+
+// exponentialLowerBound returns the lower bound of bucket i at the given
+// scale.
+func exponentialLowerBound(i int64, scale int32) float64 {
+	return math.Exp2(float64(i) * math.Exp2(-float64(scale)))
+}
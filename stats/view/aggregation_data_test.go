@@ -16,6 +16,7 @@
 package view
 
 import (
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -140,6 +141,203 @@ func TestDistributionData_addSample(t *testing.T) {
 	}
 }
 
+func TestExemplarPolicy(t *testing.T) {
+	sampled := &exemplar.Exemplar{Value: 1, Attachments: exemplar.Attachments{exemplar.KeyTraceID: "abcd"}}
+	unsampled := &exemplar.Exemplar{Value: 2}
+
+	sum := &SumData{policy: exemplar.Latest()}
+	sum.addSample(sampled)
+	sum.addSample(unsampled)
+	if want := []*exemplar.Exemplar{unsampled}; !reflect.DeepEqual(sum.Exemplars, want) {
+		t.Errorf("Latest: Exemplars = %v; want %v", sum.Exemplars, want)
+	}
+
+	lv := &LastValueData{policy: exemplar.TraceSampled()}
+	lv.addSample(unsampled)
+	if len(lv.Exemplars) != 0 {
+		t.Errorf("TraceSampled: Exemplars = %v; want none", lv.Exemplars)
+	}
+	lv.addSample(sampled)
+	lv.addSample(unsampled)
+	if want := []*exemplar.Exemplar{sampled}; !reflect.DeepEqual(lv.Exemplars, want) {
+		t.Errorf("TraceSampled: Exemplars = %v; want %v", lv.Exemplars, want)
+	}
+
+	dd := newDistributionData([]float64{10})
+	dd.policy = exemplar.Reservoir(2)
+	for i := 0; i < 100; i++ {
+		dd.addSample(&exemplar.Exemplar{Value: float64(i % 5)})
+	}
+	snapshot := dd.clone().(*DistributionData)
+	dd.addSample(&exemplar.Exemplar{Value: 20})
+	if got := len(dd.BucketExemplars[0]); got != 2 {
+		t.Errorf("Reservoir(2): %d exemplars in the first bucket; want 2", got)
+	}
+	if got := dd.ExemplarsPerBucket[0]; got != dd.BucketExemplars[0][1] {
+		t.Errorf("Reservoir(2): ExemplarsPerBucket[0] = %v; want the last retained exemplar", got)
+	}
+	if got := len(dd.BucketExemplars[1]); got != 1 {
+		t.Errorf("Reservoir(2): %d exemplars in the second bucket; want 1", got)
+	}
+	if got := len(snapshot.BucketExemplars[1]); got != 0 {
+		t.Errorf("Reservoir(2): clone changed by later samples")
+	}
+}
+
+func TestQuantileData(t *testing.T) {
+	qd := newQuantileData([]float64{0.5, 0.9}, 5*time.Second)
+	for i := 1; i <= 10; i++ {
+		// The window ages samples from when they are added, not from
+		// their timestamp.
+		qd.addSample(&exemplar.Exemplar{Value: float64(i), Timestamp: time.Now().Add(-time.Hour)})
+	}
+	start := time.Now()
+	values, count, sum := qd.window.snapshot(start.Add(time.Second))
+	if want := []float64{5, 9}; !reflect.DeepEqual(values, want) {
+		t.Errorf("quantiles = %v; want %v", values, want)
+	}
+	if count != 10 || sum != 55 {
+		t.Errorf("window count, sum = %v, %v; want 10, 55", count, sum)
+	}
+
+	// Values older than the window are dropped from the quantiles but are
+	// kept in the cumulative count and sum.
+	qd.Count++
+	qd.Sum += 100
+	qd.window.add(start.Add(6*time.Second), 100)
+	values, count, sum = qd.window.snapshot(start.Add(6 * time.Second))
+	if want := []float64{100, 100}; !reflect.DeepEqual(values, want) {
+		t.Errorf("quantiles = %v; want %v", values, want)
+	}
+	if count != 1 || sum != 100 {
+		t.Errorf("window count, sum = %v, %v; want 1, 100", count, sum)
+	}
+	if qd.Count != 11 || qd.Sum != 155 {
+		t.Errorf("count, sum = %v, %v; want 11, 155", qd.Count, qd.Sum)
+	}
+
+	values, count, _ = qd.window.snapshot(start.Add(time.Minute))
+	if count != 0 || !math.IsNaN(values[0]) {
+		t.Errorf("quantiles of an empty window = %v (count %v); want NaN", values, count)
+	}
+}
+
+func TestExponentialIndex(t *testing.T) {
+	tests := []struct {
+		v     float64
+		scale int32
+		want  int64
+	}{
+		{1, 0, 0},
+		{1.5, 0, 0},
+		{2, 0, 1},
+		{4, 0, 2},
+		{0.5, 0, -1},
+		{4, -1, 1},
+		{3, -1, 0},
+		{math.Sqrt2, 1, 1},
+		{1.4, 1, 0},
+		{2, 1, 2},
+		{1024, 3, 80},
+	}
+	for _, tt := range tests {
+		if got := exponentialIndex(tt.v, tt.scale); got != tt.want {
+			t.Errorf("exponentialIndex(%v, %d) = %d; want %d", tt.v, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestExponentialData(t *testing.T) {
+	values := []float64{-1, 0, 0.3, 1, 2, 3, 100, 1000, 7.5, 1e6}
+	ed := newExponentialData(MaxExponentialScale, 20)
+	for _, v := range values {
+		ed.addSample(&exemplar.Exemplar{Value: v})
+	}
+	if got := int64(len(ed.CountPerBucket)); got > 20 {
+		t.Errorf("got %d buckets; want at most 20", got)
+	}
+	if ed.Scale >= MaxExponentialScale {
+		t.Errorf("scale = %d; want it decreased", ed.Scale)
+	}
+	if ed.ZeroCount != 2 {
+		t.Errorf("zero count = %d; want 2", ed.ZeroCount)
+	}
+
+	// The conversion to explicit bounds is lossless: each value falls in the
+	// same bucket of a DistributionData with the same bounds.
+	got := ed.DistributionData()
+	want := newDistributionData(ed.Bounds())
+	for _, v := range values {
+		want.addSample(&exemplar.Exemplar{Value: v})
+	}
+	if diff := cmpDD(got, want); diff != "" {
+		t.Errorf("Unexpected DistributionData -got +want: %s", diff)
+	}
+	if math.Abs(got.SumOfSquaredDev-want.SumOfSquaredDev) > epsilon {
+		t.Errorf("SumOfSquaredDev = %v; want %v", got.SumOfSquaredDev, want.SumOfSquaredDev)
+	}
+	if got := got.CountPerBucket[len(got.CountPerBucket)-1]; got != 0 {
+		t.Errorf("last bucket count = %d; want 0", got)
+	}
+
+	empty := newExponentialData(0, 2)
+	empty.addSample(&exemplar.Exemplar{Value: 0})
+	if got := empty.DistributionData().CountPerBucket; !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("CountPerBucket without positive values = %v; want [1]", got)
+	}
+}
+
+func TestSub(t *testing.T) {
+	before := []float64{0.5, 1, 2}
+	after := []float64{3, 100, 1000, 1e6}
+	add := func(d AggregationData, values []float64) {
+		for _, v := range values {
+			d.addSample(&exemplar.Exemplar{Value: v})
+		}
+	}
+
+	dd := newDistributionData([]float64{1, 10})
+	add(dd, before)
+	prev := dd.clone()
+	if got := dd.sub(prev); got != nil {
+		t.Errorf("distribution sub without new samples = %v; want nil", got)
+	}
+	add(dd, after)
+	wantDD := newDistributionData([]float64{1, 10})
+	add(wantDD, after)
+	wantDD.Min, wantDD.Max = dd.Min, dd.Max
+	gotDD := dd.sub(prev).(*DistributionData)
+	if !gotDD.equal(wantDD) {
+		t.Errorf("distribution sub = %v; want %v", gotDD, wantDD)
+	}
+	if gotDD.ExemplarsPerBucket[0] != nil {
+		t.Errorf("distribution sub kept the exemplar of a bucket without new samples")
+	}
+
+	// The previous snapshot is downscaled to the scale of the data.
+	ed := newExponentialData(MaxExponentialScale, 4)
+	add(ed, before)
+	prevED := ed.clone()
+	add(ed, after)
+	if ed.Scale >= prevED.(*ExponentialData).Scale {
+		t.Fatalf("scale = %d; want it decreased", ed.Scale)
+	}
+	gotED := ed.sub(prevED).(*ExponentialData)
+	var n int64
+	for _, c := range gotED.CountPerBucket {
+		n += c
+	}
+	if gotED.Count != 4 || n != 4 || gotED.ZeroCount != 0 {
+		t.Errorf("exponential sub count = %d, bucket total %d, zero count %d; want 4, 4, 0", gotED.Count, n, gotED.ZeroCount)
+	}
+	if want := wantDD.Mean; math.Abs(gotED.Mean-want) > epsilon {
+		t.Errorf("exponential sub mean = %v; want %v", gotED.Mean, want)
+	}
+	if want := wantDD.SumOfSquaredDev; math.Abs(gotED.SumOfSquaredDev-want)/want > epsilon {
+		t.Errorf("exponential sub SumOfSquaredDev = %v; want %v", gotED.SumOfSquaredDev, want)
+	}
+}
+
 func cmpDD(got, want *DistributionData) string {
 	return cmp.Diff(got, want, cmpopts.IgnoreFields(DistributionData{}, "SumOfSquaredDev"), cmpopts.IgnoreUnexported(DistributionData{}))
 }
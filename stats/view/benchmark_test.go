@@ -90,3 +90,74 @@ func BenchmarkRecordReqCommand(b *testing.B) {
 		record.handleCommand(w)
 	}
 }
+
+// BenchmarkRecord benchmarks recording through stats.Record with a
+// registered view, including the aggregation done by the worker.
+func BenchmarkRecord(b *testing.B) {
+	benchmarkRecord(b, false)
+}
+
+// BenchmarkRecord_Parallel benchmarks recording through stats.Record from
+// concurrent goroutines.
+func BenchmarkRecord_Parallel(b *testing.B) {
+	benchmarkRecord(b, true)
+}
+
+// BenchmarkRecordBound benchmarks recording through a bound measure.
+func BenchmarkRecordBound(b *testing.B) {
+	restart()
+	v := &View{
+		Name:        "benchmark/bound",
+		Measure:     m,
+		Aggregation: Distribution(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		TagKeys:     []tag.Key{k1, k2},
+	}
+	if err := Register(v); err != nil {
+		b.Fatal(err)
+	}
+	defer Unregister(v)
+	ctx, _ := tag.New(context.Background(), tag.Upsert(k1, "v1"), tag.Upsert(k2, "v2"))
+	bm := m.Bind(ctx)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bm.Record(context.Background(), 1)
+	}
+	if _, err := RetrieveData(v.Name); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func benchmarkRecord(b *testing.B, parallel bool) {
+	restart()
+	v := &View{
+		Name:        "benchmark/record",
+		Measure:     m,
+		Aggregation: Distribution(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		TagKeys:     []tag.Key{k1, k2},
+	}
+	if err := Register(v); err != nil {
+		b.Fatal(err)
+	}
+	defer Unregister(v)
+	ctx, _ := tag.New(context.Background(), tag.Upsert(k1, "v1"), tag.Upsert(k2, "v2"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	if parallel {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				stats.Record(ctx, m.M(1))
+			}
+		})
+	} else {
+		for i := 0; i < b.N; i++ {
+			stats.Record(ctx, m.M(1))
+		}
+	}
+	// Wait for the worker to aggregate all the recorded samples.
+	if _, err := RetrieveData(v.Name); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -17,6 +17,7 @@ package view
 
 import (
 	"sort"
+	"time"
 
 	"go.opencensus.io/exemplar"
 
@@ -31,15 +32,62 @@ type collector struct {
 	// Aggregation is the description of the aggregation to perform for this
 	// view.
 	a *Aggregation
+	// policy is the exemplar policy of the aggregation data, if any.
+	policy exemplar.Policy
+	// lastUpdate holds the time the last sample of each signature was
+	// aggregated, if the rows can expire.
+	lastUpdate map[string]time.Time
 }
 
-func (c *collector) addSample(s string, e *exemplar.Exemplar) {
+func newCollector(a *Aggregation, policy exemplar.Policy, expires bool) *collector {
+	c := &collector{
+		signatures: make(map[string]AggregationData),
+		a:          a,
+		policy:     policy,
+	}
+	if expires {
+		c.lastUpdate = make(map[string]time.Time)
+	}
+	return c
+}
+
+// addSample adds e to the row with the signature s. now is the time the
+// sample is aggregated, which may differ from the time of e.
+func (c *collector) addSample(s string, e *exemplar.Exemplar, now time.Time) {
 	aggregator, ok := c.signatures[s]
 	if !ok {
-		aggregator = c.a.newData()
+		aggregator = c.newData()
 		c.signatures[s] = aggregator
 	}
 	aggregator.addSample(e)
+	if c.lastUpdate != nil {
+		c.lastUpdate[s] = now
+	}
+}
+
+// newData returns new aggregation data retaining exemplars with the policy
+// of the collector.
+func (c *collector) newData() AggregationData {
+	d := c.a.newData()
+	if c.policy == nil {
+		return d
+	}
+	switch d := d.(type) {
+	case *SumData:
+		d.policy = c.policy
+	case *LastValueData:
+		d.policy = c.policy
+	case *DistributionData:
+		d.policy = c.policy
+	}
+	return d
+}
+
+func (c *collector) deleteRow(s string) {
+	delete(c.signatures, s)
+	if c.lastUpdate != nil {
+		delete(c.lastUpdate, s)
+	}
 }
 
 // collectRows returns a snapshot of the collected Row values.
@@ -55,6 +103,9 @@ func (c *collector) collectedRows(keys []tag.Key) []*Row {
 
 func (c *collector) clearRows() {
 	c.signatures = make(map[string]AggregationData)
+	if c.lastUpdate != nil {
+		c.lastUpdate = make(map[string]time.Time)
+	}
 }
 
 // encodeWithKeys encodes the map by using values
@@ -70,6 +121,18 @@ func encodeWithKeys(m *tag.Map, keys []tag.Key) []byte {
 	return vb.Bytes()
 }
 
+// overflowSignature returns the signature of the row whose tag values are
+// all OverflowTagValue.
+func overflowSignature(keys []tag.Key) string {
+	vb := &tagencoding.Values{
+		Buffer: make([]byte, len(keys)),
+	}
+	for range keys {
+		vb.WriteValue([]byte(OverflowTagValue))
+	}
+	return string(vb.Bytes())
+}
+
 // decodeTags decodes tags from the buffer and
 // orders them by the keys.
 func decodeTags(buf []byte, keys []tag.Key) []tag.Tag {
@@ -27,20 +27,54 @@
 // how many recorded measurements fall into each bucket.
 // Sum adds up the measurement values.
 // LastValue just keeps track of the most recently recorded measurement value.
-// All aggregations are cumulative.
+// Quantiles estimates quantiles of the measurement values recorded during a
+// sliding window.
+// All aggregations are cumulative, unless an exporter is registered with
+// Delta temporality.
 //
 // Views can be registerd and unregistered at any time during program execution.
 //
+// The number of rows of a view can be limited by its MaxRows field, and the
+// number of rows of all the views by SetMaxRows. Once a limit is reached,
+// samples with new tag values are folded into an overflow row whose tag values
+// are all OverflowTagValue. The overflow rows can exceed the limit set by
+// SetMaxRows by one row per view. SetOverflowHandler reports the views
+// affected after each reporting period.
+// Rows without samples for the RowTTL of their view are dropped, and reported
+// to exporters in Data.Expired.
+//
+// Sum, LastValue and Distribution rows keep exemplars of the recorded samples
+// chosen by the ExemplarPolicy of their view, such as exemplar.Latest,
+// exemplar.TraceSampled or exemplar.Reservoir.
+//
 // Libraries can define views but it is recommended that in most cases registering
 // views be left up to applications.
 //
+// Applications can register views defined by libraries with fewer tag keys or
+// collapsed tag values by transforming them with View.Transform, for example
+// with DropTagKeys, MapTagValue or RewriteTagValue.
+//
 // Exporting
 //
 // Collected and aggregated data can be exported to a metric collection
 // backend by registering its exporter.
 //
 // Multiple exporters can be registered to upload the data to various
-// different back ends.
+// different back ends. Each exporter can be restricted to some of the views
+// with WithViews or WithViewFilter, and reported to at its own period with
+// WithReportingPeriod.
+//
+// Programs exiting before the next reporting period should call Flush or
+// Shutdown to report the data collected since the previous one.
+//
+// The data of the registered views can also be read as metrics from
+// MetricProducer.
+//
+// Meter
+//
+// The package functions register views and exporters with a default Meter.
+// NewMeter creates a Meter with its own views, exporters and reporting
+// period, to which measurements are recorded with stats.RecordTo.
 package view // import "go.opencensus.io/stats/view"
 
 // TODO(acetechnologist): Add a link to the language independent OpenCensus
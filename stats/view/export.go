@@ -14,13 +14,83 @@
 
 package view
 
-import "sync"
+import "time"
 
-var (
-	exportersMu sync.RWMutex // guards exporters
-	exporters   = make(map[Exporter]struct{})
+// Temporality describes the interval the data reported to an
+// Exporter has been aggregated over.
+type Temporality int
+
+const (
+	// Cumulative data is aggregated since the view was registered.
+	// This is the default.
+	Cumulative Temporality = iota
+
+	// Delta data is aggregated over a single reporting interval.
+	// Data.Start is the end of the previous report to the exporter, or
+	// the time it was registered, and Data.End the end of this one. Count,
+	// Sum and Distribution rows only aggregate the samples recorded during
+	// the interval, and rows without such samples are omitted; the Min and
+	// Max of Distribution rows are those of all the samples of the row.
+	// LastValue rows report the last value as a gauge.
+	Delta
 )
 
+type exporterOptions struct {
+	temporality Temporality
+	filters     []func(*View) bool
+	period      time.Duration
+}
+
+// selects returns true if the filters of the exporter select v.
+func (o exporterOptions) selects(v *View) bool {
+	for _, f := range o.filters {
+		if !f(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExporterOption configures how data is reported to an Exporter.
+type ExporterOption func(*exporterOptions)
+
+// WithTemporality sets the temporality of the data reported to the exporter.
+func WithTemporality(t Temporality) ExporterOption {
+	return func(o *exporterOptions) {
+		o.temporality = t
+	}
+}
+
+// WithViews restricts the data reported to the exporter to the views with
+// the given names.
+func WithViews(names ...string) ExporterOption {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return WithViewFilter(func(v *View) bool {
+		return set[v.Name]
+	})
+}
+
+// WithViewFilter restricts the data reported to the exporter to the views
+// for which filter returns true. If several filters are given, a view is
+// reported if all of them return true.
+func WithViewFilter(filter func(*View) bool) ExporterOption {
+	return func(o *exporterOptions) {
+		o.filters = append(o.filters, filter)
+	}
+}
+
+// WithReportingPeriod reports to the exporter at its own period instead of
+// the period set by SetReportingPeriod. If d is less than or equal to zero,
+// the period set by SetReportingPeriod is used.
+func WithReportingPeriod(d time.Duration) ExporterOption {
+	return func(o *exporterOptions) {
+		o.period = d
+	}
+}
+
 // Exporter exports the collected records as view data.
 //
 // The ExportView method should return quickly; if an
@@ -35,24 +105,56 @@ type Exporter interface {
 	ExportView(viewData *Data)
 }
 
+// Flusher is implemented by exporters that buffer the data reported to them.
+// Flush and Shutdown call Flush to send the buffered data; it should
+// return once the data is sent.
+type Flusher interface {
+	Flush()
+}
+
 // RegisterExporter registers an exporter.
 // Collected data will be reported via all the
 // registered exporters. Once you no longer
 // want data to be exported, invoke UnregisterExporter
 // with the previously registered exporter.
 //
+// Options can restrict the views reported to the exporter and report to it
+// at its own period. Registering an exporter again replaces its options.
+//
 // Binaries can register exporters, libraries shouldn't register exporters.
-func RegisterExporter(e Exporter) {
-	exportersMu.Lock()
-	defer exportersMu.Unlock()
-
-	exporters[e] = struct{}{}
+func RegisterExporter(e Exporter, opts ...ExporterOption) {
+	defaultWorker.registerExporter(e, opts...)
 }
 
 // UnregisterExporter unregisters an exporter.
 func UnregisterExporter(e Exporter) {
-	exportersMu.Lock()
-	defer exportersMu.Unlock()
+	defaultWorker.unregisterExporter(e)
+}
+
+func (w *worker) registerExporter(e Exporter, opts ...ExporterOption) {
+	var o exporterOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	w.exportersMu.Lock()
+	prev, ok := w.exporters[e]
+	scheduled := ok && prev.period > 0
+	delta := ok && prev.temporality == Delta
+	w.exporters[e] = o
+	w.exportersMu.Unlock()
+
+	if o.temporality == Delta && !delta {
+		w.send(&startDeltaReq{e: e})
+	}
+	if o.period > 0 || scheduled {
+		w.send(&scheduleReportsReq{e: e})
+	}
+}
+
+func (w *worker) unregisterExporter(e Exporter) {
+	w.exportersMu.Lock()
+	defer w.exportersMu.Unlock()
 
-	delete(exporters, e)
+	delete(w.exporters, e)
 }
@@ -0,0 +1,132 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package view
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/metric/metricexport"
+	"go.opencensus.io/tag"
+)
+
+// Meter collects data for its own views and reports it to its own exporters,
+// at its own reporting period. It lets libraries and tests keep their views
+// apart from the views registered with the package functions, which use a
+// default Meter.
+//
+// Measurements are recorded to a Meter with stats.RecordTo or the
+// stats.WithRecorder option, and bound to it with the BindTo method of the
+// measures.
+type Meter struct {
+	w *worker
+}
+
+// NewMeter returns a new started Meter. Call Stop to release its resources
+// once it is no longer used.
+func NewMeter() *Meter {
+	w := newWorker()
+	go w.start()
+	return &Meter{w: w}
+}
+
+// Stop stops the Meter without flushing it. Stop can be called more than
+// once, and after Shutdown. Once m is stopped, its methods do nothing;
+// Register and RetrieveData return an error.
+func (m *Meter) Stop() {
+	m.w.stop()
+}
+
+// Flush reports the data of the views of m to its exporters and flushes
+// them. See Flush.
+func (m *Meter) Flush() {
+	m.w.flush()
+}
+
+// Shutdown flushes m like Flush and stops it. See Shutdown.
+func (m *Meter) Shutdown(ctx context.Context) error {
+	return m.w.shutdown(ctx)
+}
+
+// Find returns the view registered with m under the given name.
+// If no registered view is found, nil is returned.
+func (m *Meter) Find(name string) *View {
+	return m.w.find(name)
+}
+
+// Register begins collecting data for the given views in m.
+// See Register.
+func (m *Meter) Register(views ...*View) error {
+	return m.w.register(views...)
+}
+
+// Unregister the given views from m. See Unregister.
+func (m *Meter) Unregister(views ...*View) {
+	m.w.unregister(views...)
+}
+
+// RegisteredViews returns the views registered with m, sorted by name.
+func (m *Meter) RegisteredViews() []ViewInfo {
+	return m.w.registeredViews()
+}
+
+// RetrieveData gets a snapshot of the data collected by m for the view
+// registered with the given name. It is intended for testing only.
+func (m *Meter) RetrieveData(viewName string) ([]*Row, error) {
+	return m.w.retrieveData(viewName)
+}
+
+// Record records the measurements ms, of type []stats.Measurement, made at
+// t with the given tags and exemplar attachments. It implements
+// stats.Recorder.
+func (m *Meter) Record(tags *tag.Map, ms interface{}, attachments map[string]string, t time.Time) {
+	m.w.recordMeasurements(tags, ms, attachments, t)
+}
+
+// SetReportingPeriod sets the interval between reports of the views of m.
+// See SetReportingPeriod.
+func (m *Meter) SetReportingPeriod(d time.Duration) {
+	m.w.setReportingPeriod(d)
+}
+
+// SetMaxRows sets the maximum number of rows of all the views of m together.
+// See SetMaxRows.
+func (m *Meter) SetMaxRows(n int) {
+	m.w.setMaxRows(n)
+}
+
+// SetOverflowHandler sets the function reporting the views of m that folded
+// samples into their overflow row. See SetOverflowHandler.
+func (m *Meter) SetOverflowHandler(h func([]OverflowReport)) {
+	m.w.setOverflowHandler(h)
+}
+
+// RegisterExporter registers an exporter for the views of m.
+// See RegisterExporter.
+func (m *Meter) RegisterExporter(e Exporter, opts ...ExporterOption) {
+	m.w.registerExporter(e, opts...)
+}
+
+// UnregisterExporter unregisters an exporter from m.
+func (m *Meter) UnregisterExporter(e Exporter) {
+	m.w.unregisterExporter(e)
+}
+
+// MetricProducer returns a metricexport.Producer that reads the data
+// collected by the views of m as metrics. See MetricProducer.
+func (m *Meter) MetricProducer() metricexport.Producer {
+	return producer{m.w}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package view
+
+import (
+	"math"
+	"time"
+
+	"github.com/beorn7/perks/quantile"
+)
+
+// quantileAgeBuckets is the number of streams a quantileWindow is made of.
+const quantileAgeBuckets = 5
+
+// quantileWindow estimates quantiles over the values recorded during a
+// sliding window of maxAge.
+//
+// Every value is inserted into all the streams of the window. The streams
+// are reset in turn every maxAge/quantileAgeBuckets, and the quantiles
+// are read from the stream reset the longest ago, which holds the values of
+// the last maxAge at most.
+type quantileWindow struct {
+	quantiles []float64
+	width     time.Duration // time between two stream resets
+	streams   [quantileAgeBuckets]windowStream
+	head      int       // index of the stream reset the longest ago
+	headReset time.Time // time at which the head stream is reset
+}
+
+type windowStream struct {
+	*quantile.Stream
+	sum float64
+}
+
+func newQuantileWindow(quantiles []float64, maxAge time.Duration) *quantileWindow {
+	targets := make(map[float64]float64, len(quantiles))
+	for _, q := range quantiles {
+		targets[q] = math.Max(0.1*math.Min(q, 1-q), 0.001)
+	}
+	w := &quantileWindow{
+		quantiles: quantiles,
+		width:     maxAge / quantileAgeBuckets,
+	}
+	for i := range w.streams {
+		w.streams[i].Stream = quantile.NewTargeted(targets)
+	}
+	return w
+}
+
+func (w *quantileWindow) add(t time.Time, v float64) {
+	w.rotate(t)
+	for i := range w.streams {
+		w.streams[i].Insert(v)
+		w.streams[i].sum += v
+	}
+}
+
+// snapshot returns the estimated quantiles, the number and the sum of the
+// values in the window as of now.
+func (w *quantileWindow) snapshot(now time.Time) (values []float64, count int64, sum float64) {
+	w.rotate(now)
+	head := w.streams[w.head]
+	values = make([]float64, len(w.quantiles))
+	for i, q := range w.quantiles {
+		if head.Count() == 0 {
+			values[i] = math.NaN()
+		} else {
+			values[i] = head.Query(q)
+		}
+	}
+	return values, int64(head.Count()), head.sum
+}
+
+// rotate resets the streams whose values are older than maxAge at t.
+func (w *quantileWindow) rotate(t time.Time) {
+	if w.headReset.IsZero() {
+		w.headReset = t.Add(w.width)
+		return
+	}
+	if t.Sub(w.headReset) >= w.width*quantileAgeBuckets {
+		// Every stream has expired.
+		for i := range w.streams {
+			w.streams[i].Reset()
+			w.streams[i].sum = 0
+		}
+		w.headReset = t.Add(w.width)
+		return
+	}
+	for !t.Before(w.headReset) {
+		w.streams[w.head].Reset()
+		w.streams[w.head].sum = 0
+		w.head = (w.head + 1) % quantileAgeBuckets
+		w.headReset = w.headReset.Add(w.width)
+	}
+}
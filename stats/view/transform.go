@@ -0,0 +1,132 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package view
+
+import (
+	"regexp"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+)
+
+// Transform modifies a view. Transforms are applied with View.Transform,
+// for example to register a view defined by a library with fewer tag keys
+// or with collapsed tag values.
+type Transform func(*View)
+
+// tagValueMap maps the values of the tag with the key k.
+type tagValueMap struct {
+	k tag.Key
+	f func(string) string
+}
+
+// Transform returns a copy of the view modified by the transforms, applied
+// in order.
+func (v *View) Transform(transforms ...Transform) *View {
+	vNew := *v
+	vNew.TagKeys = append([]tag.Key(nil), v.TagKeys...)
+	vNew.tagValueMaps = append([]*tagValueMap(nil), v.tagValueMaps...)
+	for _, t := range transforms {
+		t(&vNew)
+	}
+	return &vNew
+}
+
+// KeepTagKeys removes the tag keys of the view other than keys.
+func KeepTagKeys(keys ...tag.Key) Transform {
+	keep := make(map[tag.Key]bool, len(keys))
+	for _, k := range keys {
+		keep[k] = true
+	}
+	return filterTagKeys(func(k tag.Key) bool { return keep[k] })
+}
+
+// DropTagKeys removes keys from the tag keys of the view.
+func DropTagKeys(keys ...tag.Key) Transform {
+	drop := make(map[tag.Key]bool, len(keys))
+	for _, k := range keys {
+		drop[k] = true
+	}
+	return filterTagKeys(func(k tag.Key) bool { return !drop[k] })
+}
+
+func filterTagKeys(keep func(tag.Key) bool) Transform {
+	return func(v *View) {
+		keys := v.TagKeys[:0]
+		for _, k := range v.TagKeys {
+			if keep(k) {
+				keys = append(keys, k)
+			}
+		}
+		v.TagKeys = keys
+	}
+}
+
+// MapTagValue replaces the values of the tag with the key k by the value
+// f returns for them when measurements are recorded. If f returns an empty
+// string, the measurement is aggregated as if the tag was not set.
+// Functions cannot be compared, so views mapping tag values are only the
+// same view if they were transformed by the same MapTagValue transforms.
+func MapTagValue(k tag.Key, f func(string) string) Transform {
+	m := &tagValueMap{k: k, f: f}
+	return func(v *View) {
+		v.tagValueMaps = append(v.tagValueMaps, m)
+	}
+}
+
+// RewriteTagValue replaces the matches of re in the values of the tag with
+// the key k by repl, as regexp.Regexp.ReplaceAllString does.
+func RewriteTagValue(k tag.Key, re *regexp.Regexp, repl string) Transform {
+	return MapTagValue(k, func(s string) string {
+		return re.ReplaceAllString(s, repl)
+	})
+}
+
+// ToUnit sets the unit of the view to unit, written as described by
+// stats.ParseUnit, and converts the bucket bounds of Distribution
+// aggregations from the previous unit of the view. The recorded values are
+// converted for all the aggregations; the parameters of the other
+// aggregations do not depend on the unit and are kept.
+func ToUnit(unit string) Transform {
+	return func(v *View) {
+		from := v.Unit
+		if from == "" && v.Measure != nil {
+			from = v.Measure.Unit()
+		}
+		v.Unit = unit
+		scale, err := stats.UnitConversion(from, unit)
+		if err != nil || v.Aggregation == nil || v.Aggregation.Type != AggTypeDistribution {
+			// Registering the view reports units that cannot be converted.
+			return
+		}
+		bounds := make([]float64, len(v.Aggregation.Buckets))
+		for i, b := range v.Aggregation.Buckets {
+			bounds[i] = b * scale
+		}
+		v.Aggregation = Distribution(bounds...)
+	}
+}
+
+// mapTagValue returns the value of the tag with the key k once mapped by
+// the value maps of the view.
+func (v *View) mapTagValue(k tag.Key, value string) string {
+	for _, m := range v.tagValueMaps {
+		if m.k == k {
+			value = m.f(value)
+		}
+	}
+	return value
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"go.opencensus.io/exemplar"
+	"go.opencensus.io/internal/tagencoding"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/internal"
@@ -47,6 +48,47 @@ type View struct {
 
 	// Aggregation is the aggregation function tp apply to the set of Measurements.
 	Aggregation *Aggregation
+
+	// Unit is the unit of the aggregated values and of the bucket bounds,
+	// written as described by stats.ParseUnit. If unset, will default to
+	// the unit of the Measure. Recorded values are converted from the unit
+	// of the Measure, which must measure the same quantity.
+	Unit string
+
+	// MaxRows is the maximum number of rows of this view. Samples that would
+	// create a row beyond it are folded into the overflow row, whose tag values
+	// are all OverflowTagValue. If zero or negative, only the limit set by
+	// SetMaxRows applies.
+	MaxRows int
+
+	// RowTTL is the time after which a row without new samples is dropped.
+	// Dropped rows are reported once in Data.Expired, and rows recreated
+	// later with the same tags start again from zero. If zero or negative,
+	// rows are never dropped.
+	RowTTL time.Duration
+
+	// ExemplarPolicy decides which exemplars the rows of this view retain.
+	// It applies to Sum, LastValue and Distribution aggregations. If nil,
+	// Sum and LastValue rows retain no exemplars and Distribution rows
+	// retain one per bucket, preferring those of sampled traces.
+	ExemplarPolicy exemplar.Policy
+
+	// tagValueMaps map the tag values of the measurements before they are
+	// aggregated. See MapTagValue.
+	tagValueMaps []*tagValueMap
+}
+
+// OverflowTagValue is the value of every tag of the row into which samples
+// are folded once a view reaches its row limit.
+const OverflowTagValue = "__overflow__"
+
+// OverflowReport reports a view whose samples are folded into its overflow
+// row because it reached a row limit.
+type OverflowReport struct {
+	View *View
+	// Folded is the number of samples folded into the overflow row during
+	// the reporting period.
+	Folded int64
 }
 
 // WithName returns a copy of the View with a new name. This is useful for
@@ -66,12 +108,32 @@ func (v *View) same(other *View) bool {
 	if v == nil {
 		return false
 	}
+	if len(v.TagKeys) != len(other.TagKeys) || len(v.tagValueMaps) != len(other.tagValueMaps) {
+		return false
+	}
+	for i, k := range v.TagKeys {
+		if k != other.TagKeys[i] {
+			return false
+		}
+	}
+	// Tag value maps are the same only if they come from the same
+	// MapTagValue transforms.
+	for i, m := range v.tagValueMaps {
+		if m != other.tagValueMaps[i] {
+			return false
+		}
+	}
 	return reflect.DeepEqual(v.Aggregation, other.Aggregation) &&
-		v.Measure.Name() == other.Measure.Name()
+		v.Measure.Name() == other.Measure.Name() &&
+		v.Unit == other.Unit
 }
 
 var ErrNegativeBucketBounds = errors.New("negative bucket bounds not supported")
 
+var ErrInvalidQuantiles = errors.New("quantiles must be in the range (0, 1] and the window duration positive")
+
+var ErrInvalidExponentialDistribution = errors.New("exponential distribution scale out of range or fewer than 2 buckets")
+
 // canonicalize canonicalizes v by setting explicit
 // defaults for Name and Description and sorting the TagKeys
 func (v *View) canonicalize() error {
@@ -87,6 +149,12 @@ func (v *View) canonicalize() error {
 	if v.Description == "" {
 		v.Description = v.Measure.Description()
 	}
+	if v.Unit == "" {
+		v.Unit = v.Measure.Unit()
+	}
+	if _, err := stats.UnitConversion(v.Measure.Unit(), v.Unit); err != nil {
+		return fmt.Errorf("cannot register view %q: %v", v.Name, err)
+	}
 	if err := checkViewName(v.Name); err != nil {
 		return err
 	}
@@ -102,6 +170,23 @@ func (v *View) canonicalize() error {
 	// drop 0 bucket silently.
 	v.Aggregation.Buckets = dropZeroBounds(v.Aggregation.Buckets...)
 
+	if v.Aggregation.Type == AggTypeQuantiles {
+		if len(v.Aggregation.Quantiles) == 0 || v.Aggregation.MaxAge <= 0 {
+			return ErrInvalidQuantiles
+		}
+		for _, q := range v.Aggregation.Quantiles {
+			if q <= 0 || q > 1 {
+				return ErrInvalidQuantiles
+			}
+		}
+		sort.Float64s(v.Aggregation.Quantiles)
+	}
+	if v.Aggregation.Type == AggTypeExponential {
+		if v.Aggregation.MaxScale < MinExponentialScale || v.Aggregation.MaxScale > MaxExponentialScale || v.Aggregation.MaxBuckets < 2 {
+			return ErrInvalidExponentialDistribution
+		}
+	}
+
 	return nil
 }
 
@@ -119,12 +204,57 @@ type viewInternal struct {
 	view       *View  // view is the canonicalized View definition associated with this view.
 	subscribed uint32 // 1 if someone is subscribed and data need to be exported, use atomic to access
 	collector  *collector
+	// intervals holds the interval since the view was last reported to
+	// each exporter that receives Delta data.
+	intervals map[Exporter]*interval
+
+	limit       *rowLimit // limit is shared by all the views of a worker; nil for no limit.
+	overflowSig string    // overflowSig is the signature of the overflow row.
+	folded      int64     // folded is the number of samples folded into the overflow row.
+	// reportedFolded is the value of folded at the last overflow report.
+	reportedFolded int64
+
+	// expired holds the rows dropped since the view was last reported to
+	// each exporter.
+	expired map[Exporter][]*Row
+
+	// scale converts the recorded values to the unit of the view.
+	scale float64
+}
+
+// rowLimit bounds the total number of rows of a set of views.
+type rowLimit struct {
+	max  int // max is the maximum number of rows; zero or negative for no limit.
+	rows int
+}
+
+func (l *rowLimit) full() bool {
+	return l != nil && l.max > 0 && l.rows >= l.max
+}
+
+// interval is the reporting interval of a view to a Delta exporter. The
+// data of the interval is the difference between the rows of the view and
+// prev, their snapshot at start.
+type interval struct {
+	start time.Time
+	prev  map[string]AggregationData
 }
 
 func newViewInternal(v *View) (*viewInternal, error) {
+	scale := 1.0
+	if v.Unit != "" && v.Measure != nil {
+		var err error
+		if scale, err = stats.UnitConversion(v.Measure.Unit(), v.Unit); err != nil {
+			return nil, err
+		}
+	}
 	return &viewInternal{
-		view:      v,
-		collector: &collector{make(map[string]AggregationData), v.Aggregation},
+		scale:       scale,
+		view:        v,
+		collector:   newCollector(v.Aggregation, v.ExemplarPolicy, v.RowTTL > 0),
+		intervals:   make(map[Exporter]*interval),
+		overflowSig: overflowSignature(v.TagKeys),
+		expired:     make(map[Exporter][]*Row),
 	}, nil
 }
 
@@ -143,19 +273,167 @@ func (v *viewInternal) isSubscribed() bool {
 }
 
 func (v *viewInternal) clearRows() {
+	if v.limit != nil {
+		v.limit.rows -= len(v.collector.signatures)
+	}
 	v.collector.clearRows()
+	for _, i := range v.intervals {
+		i.prev = nil
+	}
 }
 
 func (v *viewInternal) collectedRows() []*Row {
 	return v.collector.collectedRows(v.view.TagKeys)
 }
 
-func (v *viewInternal) addSample(m *tag.Map, e *exemplar.Exemplar) {
+func (v *viewInternal) addSample(m *tag.Map, e *exemplar.Exemplar, now time.Time) {
+	if !v.isSubscribed() {
+		return
+	}
+	v.addSampleWithSignature(v.signature(m), e, now)
+}
+
+// signature returns the signature of the row of the tags in m.
+func (v *viewInternal) signature(m *tag.Map) string {
+	if len(v.view.tagValueMaps) == 0 {
+		return string(encodeWithKeys(m, v.view.TagKeys))
+	}
+	vb := &tagencoding.Values{
+		Buffer: make([]byte, len(v.view.TagKeys)),
+	}
+	for _, k := range v.view.TagKeys {
+		value, ok := m.Value(k)
+		if ok {
+			value = v.view.mapTagValue(k, value)
+		}
+		vb.WriteValue([]byte(value))
+	}
+	return string(vb.Bytes())
+}
+
+// addSampleWithSignature adds e to the row with the signature sig, at the
+// time now.
+func (v *viewInternal) addSampleWithSignature(sig string, e *exemplar.Exemplar, now time.Time) {
 	if !v.isSubscribed() {
 		return
 	}
-	sig := string(encodeWithKeys(m, v.view.TagKeys))
-	v.collector.addSample(sig, e)
+	if v.scale != 1 {
+		e = &exemplar.Exemplar{
+			Value:       e.Value * v.scale,
+			Timestamp:   e.Timestamp,
+			Attachments: e.Attachments,
+		}
+	}
+	if _, ok := v.collector.signatures[sig]; !ok {
+		if v.rowsFull() {
+			sig = v.overflowSig
+			v.folded++
+		}
+		if _, ok := v.collector.signatures[sig]; !ok && v.limit != nil {
+			v.limit.rows++
+		}
+	}
+	v.collector.addSample(sig, e, now)
+}
+
+// expireRows drops and returns the rows without samples since now minus
+// the RowTTL of the view.
+func (v *viewInternal) expireRows(now time.Time) []*Row {
+	if v.view.RowTTL <= 0 {
+		return nil
+	}
+	var expired []*Row
+	deadline := now.Add(-v.view.RowTTL)
+	for sig, t := range v.collector.lastUpdate {
+		if !t.Before(deadline) {
+			continue
+		}
+		expired = append(expired, &Row{
+			Tags: decodeTags([]byte(sig), v.view.TagKeys),
+			Data: v.collector.signatures[sig],
+		})
+		v.collector.deleteRow(sig)
+		for _, i := range v.intervals {
+			delete(i.prev, sig)
+		}
+		if v.limit != nil {
+			v.limit.rows--
+		}
+	}
+	return expired
+}
+
+// rowsFull returns true if no row other than the overflow row can be added
+// to the view.
+func (v *viewInternal) rowsFull() bool {
+	n := len(v.collector.signatures)
+	if _, ok := v.collector.signatures[v.overflowSig]; ok {
+		n--
+	}
+	if v.view.MaxRows > 0 && n >= v.view.MaxRows {
+		return true
+	}
+	return v.limit.full()
+}
+
+// startInterval starts the first interval of e at start, with the current
+// rows of the view as its baseline.
+func (v *viewInternal) startInterval(e Exporter, start time.Time) {
+	v.intervals[e] = &interval{start: start, prev: v.snapshot()}
+}
+
+// snapshot returns a copy of the data of each row of the view.
+func (v *viewInternal) snapshot() map[string]AggregationData {
+	rows := make(map[string]AggregationData, len(v.collector.signatures))
+	for sig, d := range v.collector.signatures {
+		rows[sig] = d.clone()
+	}
+	return rows
+}
+
+// deltaData returns the data aggregated since the view was last reported to
+// e and starts a new interval at end. If e has no interval yet, the data
+// is aggregated since start. LastValue data is reported as a gauge.
+func (v *viewInternal) deltaData(e Exporter, start, end time.Time) *Data {
+	i, ok := v.intervals[e]
+	if !ok {
+		i = &interval{start: start}
+		v.intervals[e] = i
+	}
+	cur := v.snapshot()
+	var rows []*Row
+	for sig, d := range cur {
+		if p, ok := i.prev[sig]; ok {
+			d = d.sub(p)
+		} else {
+			d = d.clone()
+		}
+		if d != nil {
+			rows = append(rows, &Row{Tags: decodeTags([]byte(sig), v.view.TagKeys), Data: d})
+		}
+	}
+	vd := &Data{
+		View:  v.view,
+		Start: i.start,
+		End:   end,
+		Rows:  rows,
+	}
+	i.start = end
+	i.prev = cur
+	return vd
+}
+
+// ViewInfo describes a registered view.
+type ViewInfo struct {
+	// View is the registered view. It holds the name, description,
+	// measure, tag keys and aggregation of the view, and must not be
+	// modified.
+	View *View
+	// Rows is the number of rows of the view, including its overflow row.
+	Rows int
+	// Folded is the number of samples folded into the overflow row of the
+	// view.
+	Folded int64
 }
 
 // A Data is a set of rows about usage of the single measure associated
@@ -164,6 +442,12 @@ type Data struct {
 	View       *View
 	Start, End time.Time
 	Rows       []*Row
+
+	// Expired holds the rows dropped since the previous report because
+	// no sample was recorded for them during the RowTTL of the view, with
+	// their last data. Rows with the same tags reported later are new
+	// rows starting from zero.
+	Expired []*Row
 }
 
 // Row is the collected value for a specific set of key value pairs a.k.a tags.
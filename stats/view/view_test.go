@@ -17,7 +17,10 @@ package view
 
 import (
 	"context"
+	"regexp"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -181,7 +184,7 @@ func Test_View_MeasureFloat64_AggregationDistribution(t *testing.T) {
 				Value:       r.f,
 				Attachments: exemplar.AttachmentsFromContext(ctx),
 			}
-			view.addSample(tag.FromContext(ctx), e)
+			view.addSample(tag.FromContext(ctx), e, time.Now())
 		}
 
 		gotRows := view.collectedRows()
@@ -300,7 +303,7 @@ func Test_View_MeasureFloat64_AggregationSum(t *testing.T) {
 			e := &exemplar.Exemplar{
 				Value: r.f,
 			}
-			view.addSample(tag.FromContext(ctx), e)
+			view.addSample(tag.FromContext(ctx), e, time.Now())
 		}
 
 		gotRows := view.collectedRows()
@@ -490,3 +493,176 @@ func TestViewRegister_dropZeroBuckets(t *testing.T) {
 		t.Errorf("buckets differ -got +want: %s", diff)
 	}
 }
+
+func TestViewRegister_invalidQuantiles(t *testing.T) {
+	m := stats.Int64("TestViewRegister_invalidQuantiles", "", "")
+	for _, agg := range []*Aggregation{
+		Quantiles(),
+		Quantiles(0, 0.5),
+		Quantiles(1.5),
+		QuantilesWithMaxAge(0, 0.5),
+	} {
+		v := &View{
+			Measure:     m,
+			Aggregation: agg,
+		}
+		if err := Register(v); err != ErrInvalidQuantiles {
+			t.Errorf("Register(%v) = %v; want ErrInvalidQuantiles", agg.Quantiles, err)
+		}
+	}
+}
+
+func TestViewRegister_invalidExponentialDistribution(t *testing.T) {
+	m := stats.Int64("TestViewRegister_invalidExponentialDistribution", "", "")
+	for _, agg := range []*Aggregation{
+		ExponentialDistribution(MaxExponentialScale+1, 160),
+		ExponentialDistribution(MinExponentialScale-1, 160),
+		ExponentialDistribution(0, 1),
+	} {
+		v := &View{
+			Measure:     m,
+			Aggregation: agg,
+		}
+		if err := Register(v); err != ErrInvalidExponentialDistribution {
+			t.Errorf("Register(%d, %d) = %v; want ErrInvalidExponentialDistribution", agg.MaxScale, agg.MaxBuckets, err)
+		}
+	}
+}
+
+func TestViewTransform(t *testing.T) {
+	host, _ := tag.NewKey("host")
+	status, _ := tag.NewKey("status")
+	path, _ := tag.NewKey("path")
+	m := stats.Int64("TestViewTransform/m", "", stats.UnitDimensionless)
+	orig := &View{
+		Name:        "TestViewTransform",
+		TagKeys:     []tag.Key{host, path, status},
+		Measure:     m,
+		Aggregation: Count(),
+	}
+	v := orig.Transform(
+		DropTagKeys(host),
+		MapTagValue(status, func(s string) string { return s[:1] + "xx" }),
+		RewriteTagValue(path, regexp.MustCompile(`/users/[0-9]+`), "/users/:id"),
+	)
+	if got, want := len(orig.TagKeys), 3; got != want {
+		t.Errorf("Transform modified the original view: got %d tag keys; want %d", got, want)
+	}
+	if err := v.canonicalize(); err != nil {
+		t.Fatal(err)
+	}
+	vi, err := newViewInternal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vi.subscribe()
+
+	for _, r := range [][3]string{
+		{"a", "/users/1", "200"},
+		{"b", "/users/2", "204"},
+		{"a", "/users/3", "503"},
+	} {
+		ctx, err := tag.New(context.Background(),
+			tag.Insert(host, r[0]), tag.Insert(path, r[1]), tag.Insert(status, r[2]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		vi.addSample(tag.FromContext(ctx), &exemplar.Exemplar{Value: 1}, time.Now())
+	}
+
+	want := []*Row{
+		{Tags: []tag.Tag{{Key: path, Value: "/users/:id"}, {Key: status, Value: "2xx"}}, Data: &CountData{Value: 2}},
+		{Tags: []tag.Tag{{Key: path, Value: "/users/:id"}, {Key: status, Value: "5xx"}}, Data: &CountData{Value: 1}},
+	}
+	got := vi.collectedRows()
+	sort.Slice(got, func(i, j int) bool { return got[i].Tags[1].Value < got[j].Tags[1].Value })
+	if len(got) != len(want) {
+		t.Fatalf("got rows %v; want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("row %d = %v; want %v", i, got[i], want[i])
+		}
+	}
+
+	kept := orig.Transform(KeepTagKeys(status))
+	if got, want := kept.TagKeys, []tag.Key{status}; !cmp.Equal(got, want, cmp.Comparer(func(a, b tag.Key) bool { return a.Name() == b.Name() })) {
+		t.Errorf("KeepTagKeys: tag keys = %v; want %v", got, want)
+	}
+}
+
+func TestViewTransformSameName(t *testing.T) {
+	host, _ := tag.NewKey("host")
+	status, _ := tag.NewKey("status")
+	m := stats.Int64("TestViewTransformSameName/m", "", stats.UnitDimensionless)
+	orig := &View{
+		Name:        "TestViewTransformSameName",
+		TagKeys:     []tag.Key{host, status},
+		Measure:     m,
+		Aggregation: Count(),
+	}
+	collapse := MapTagValue(status, func(s string) string { return s[:1] + "xx" })
+	meter := NewMeter()
+	defer meter.Stop()
+	if err := meter.Register(orig.Transform(collapse)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Views transformed differently are different views.
+	for _, v := range []*View{
+		orig,
+		orig.Transform(DropTagKeys(host), collapse),
+		orig.Transform(MapTagValue(status, func(s string) string { return s[:1] + "xx" })),
+	} {
+		if err := meter.Register(v); err == nil {
+			t.Errorf("registered %v under the name of a different view", v)
+		}
+	}
+	if err := meter.Register(orig.Transform(collapse)); err != nil {
+		t.Errorf("cannot register the same transformed view again: %v", err)
+	}
+}
+
+func TestViewUnit(t *testing.T) {
+	m := stats.Int64("TestViewUnit/latency", "", "ns")
+	latency := &View{
+		Name:        "TestViewUnit/latency",
+		Measure:     m,
+		Unit:        "ms",
+		Aggregation: Distribution(1, 10),
+	}
+	seconds := latency.Transform(ToUnit("s"))
+	seconds.Name = "TestViewUnit/latency_s"
+	if got, want := seconds.Aggregation.Buckets, []float64{0.001, 0.01}; !cmp.Equal(got, want) {
+		t.Errorf("bucket bounds in s = %v; want %v", got, want)
+	}
+	if err := Register(latency, seconds); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(latency, seconds)
+
+	stats.Record(context.Background(), m.M(5e6))
+	for _, tt := range []struct {
+		v      *View
+		sum    float64
+		counts []int64
+	}{
+		{latency, 5, []int64{0, 1, 0}},
+		{seconds, 0.005, []int64{0, 1, 0}},
+	} {
+		rows, err := RetrieveData(tt.v.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := rows[0].Data.(*DistributionData)
+		if d.Sum() != tt.sum || !cmp.Equal(d.CountPerBucket, tt.counts) {
+			t.Errorf("%s: sum = %v, counts = %v; want %v, %v", tt.v.Name, d.Sum(), d.CountPerBucket, tt.sum, tt.counts)
+		}
+	}
+
+	bytes := &View{Name: "TestViewUnit/bytes", Measure: m, Unit: "By", Aggregation: Count()}
+	if err := Register(bytes); err == nil {
+		Unregister(bytes)
+		t.Errorf("registered a view in By of a measure in ns")
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package view
+
+import (
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricexport"
+	"go.opencensus.io/stats"
+)
+
+// MetricProducer returns a metricexport.Producer that reads the data
+// collected by the registered views as metrics.
+//
+// Count and Sum views are read as cumulative metrics, Distribution views as
+// cumulative distributions, ExponentialDistribution views as cumulative
+// distributions with explicit bucket bounds, LastValue views as gauges and Quantiles views as
+// summaries. Count views, and Sum and LastValue views of int64 measures,
+// have int64 points; other views have float64 points.
+func MetricProducer() metricexport.Producer {
+	return producer{}
+}
+
+// producer reads the views of w, or of the default worker if w is nil.
+type producer struct {
+	w *worker
+}
+
+func (p producer) Read() []*metricdata.Metric {
+	if p.w == nil {
+		return defaultWorker.read()
+	}
+	return p.w.read()
+}
+
+// read returns the data of the views registered with w as metrics.
+func (w *worker) read() []*metricdata.Metric {
+	req := &readMetricsReq{
+		now: time.Now(),
+		c:   make(chan []*metricdata.Metric),
+	}
+	if !w.send(req) {
+		return nil
+	}
+	return <-req.c
+}
+
+func viewToMetricDescriptor(v *View) metricdata.Descriptor {
+	keys := make([]string, len(v.TagKeys))
+	for i, k := range v.TagKeys {
+		keys[i] = k.Name()
+	}
+	return metricdata.Descriptor{
+		Name:        v.Name,
+		Description: v.Description,
+		Unit:        metricdata.Unit(v.Unit),
+		Type:        metricType(v),
+		LabelKeys:   keys,
+	}
+}
+
+func metricType(v *View) metricdata.Type {
+	_, isInt64 := v.Measure.(*stats.Int64Measure)
+	switch v.Aggregation.Type {
+	case AggTypeCount:
+		return metricdata.TypeCumulativeInt64
+	case AggTypeSum:
+		if isInt64 {
+			return metricdata.TypeCumulativeInt64
+		}
+		return metricdata.TypeCumulativeFloat64
+	case AggTypeDistribution, AggTypeExponential:
+		return metricdata.TypeCumulativeDistribution
+	case AggTypeLastValue:
+		if isInt64 {
+			return metricdata.TypeGaugeInt64
+		}
+		return metricdata.TypeGaugeFloat64
+	case AggTypeQuantiles:
+		return metricdata.TypeSummary
+	default:
+		panic("unexpected aggregation type")
+	}
+}
+
+// viewToMetric converts the rows of a view collected from start to now
+// to a metric.
+func viewToMetric(v *View, rows []*Row, start, now time.Time) *metricdata.Metric {
+	m := &metricdata.Metric{
+		Descriptor: viewToMetricDescriptor(v),
+		TimeSeries: make([]*metricdata.TimeSeries, 0, len(rows)),
+	}
+	for _, row := range rows {
+		ts := &metricdata.TimeSeries{
+			LabelValues: rowLabelValues(v, row),
+			Points:      []metricdata.Point{rowToPoint(m.Descriptor.Type, row.Data, now)},
+		}
+		if m.Descriptor.Type != metricdata.TypeGaugeInt64 && m.Descriptor.Type != metricdata.TypeGaugeFloat64 {
+			ts.StartTime = start
+		}
+		m.TimeSeries = append(m.TimeSeries, ts)
+	}
+	return m
+}
+
+func rowLabelValues(v *View, row *Row) []metricdata.LabelValue {
+	values := make([]metricdata.LabelValue, len(v.TagKeys))
+	for _, t := range row.Tags {
+		for i, k := range v.TagKeys {
+			if t.Key == k {
+				values[i] = metricdata.NewLabelValue(t.Value)
+				break
+			}
+		}
+	}
+	return values
+}
+
+func rowToPoint(t metricdata.Type, data AggregationData, now time.Time) metricdata.Point {
+	switch data := data.(type) {
+	case *CountData:
+		return metricdata.NewInt64Point(now, data.Value)
+	case *SumData:
+		p := metricdata.NewFloat64Point(now, data.Value)
+		if t == metricdata.TypeCumulativeInt64 {
+			p = metricdata.NewInt64Point(now, int64(data.Value))
+		}
+		p.Exemplars = data.Exemplars
+		return p
+	case *LastValueData:
+		p := metricdata.NewFloat64Point(now, data.Value)
+		if t == metricdata.TypeGaugeInt64 {
+			p = metricdata.NewInt64Point(now, int64(data.Value))
+		}
+		p.Exemplars = data.Exemplars
+		return p
+	case *DistributionData:
+		return metricdata.NewDistributionPoint(now, toMetricDistribution(data))
+	case *ExponentialData:
+		return metricdata.NewDistributionPoint(now, toMetricDistribution(data.DistributionData()))
+	case *QuantileData:
+		s := &metricdata.Summary{
+			Count:          data.Count,
+			Sum:            data.Sum,
+			HasCountAndSum: true,
+			Snapshot: metricdata.Snapshot{
+				Count:       data.WindowCount,
+				Sum:         data.WindowSum,
+				Percentiles: make(map[float64]float64, len(data.Quantiles)),
+			},
+		}
+		for i, q := range data.Quantiles {
+			s.Snapshot.Percentiles[q*100] = data.Values[i]
+		}
+		return metricdata.NewSummaryPoint(now, s)
+	default:
+		panic("unexpected aggregation data type")
+	}
+}
+
+func toMetricDistribution(data *DistributionData) *metricdata.Distribution {
+	d := &metricdata.Distribution{
+		Count:                 data.Count,
+		Sum:                   data.Sum(),
+		SumOfSquaredDeviation: data.SumOfSquaredDev,
+		BucketOptions:         &metricdata.BucketOptions{Bounds: data.bounds},
+		Buckets:               make([]metricdata.Bucket, len(data.CountPerBucket)),
+	}
+	for i, c := range data.CountPerBucket {
+		d.Buckets[i] = metricdata.Bucket{Count: c, Exemplar: data.ExemplarsPerBucket[i]}
+		if data.BucketExemplars != nil {
+			d.Buckets[i].Exemplars = data.BucketExemplars[i]
+		}
+	}
+	return d
+}
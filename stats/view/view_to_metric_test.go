@@ -0,0 +1,223 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package view
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"go.opencensus.io/exemplar"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+)
+
+func TestMetricProducer(t *testing.T) {
+	restart()
+	k1, _ := tag.NewKey("k1")
+	k2, _ := tag.NewKey("k2")
+	mi := stats.Int64("TestMetricProducer/int64", "int64 measure", stats.UnitBytes)
+	mf := stats.Float64("TestMetricProducer/float64", "float64 measure", stats.UnitMilliseconds)
+	views := []*View{
+		{Name: "count", Measure: mi, Aggregation: Count(), TagKeys: []tag.Key{k2, k1}},
+		{Name: "sum", Measure: mi, Aggregation: Sum()},
+		{Name: "lastvalue", Measure: mf, Aggregation: LastValue()},
+		{Name: "distribution", Measure: mf, Aggregation: Distribution(2)},
+		{Name: "quantiles", Measure: mf, Aggregation: Quantiles(0.5)},
+		{Name: "exponential", Measure: mf, Aggregation: ExponentialDistribution(0, 4)},
+	}
+	if err := Register(views...); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(views...)
+
+	ctx, _ := tag.New(context.Background(), tag.Insert(k1, "v1"))
+	stats.Record(ctx, mi.M(3), mf.M(1))
+	stats.Record(ctx, mi.M(4), mf.M(3))
+
+	metrics := make(map[string]*metricdata.Metric)
+	for _, m := range MetricProducer().Read() {
+		metrics[m.Descriptor.Name] = m
+	}
+
+	tests := []struct {
+		name       string
+		descriptor metricdata.Descriptor
+		labels     []metricdata.LabelValue
+		value      interface{}
+	}{
+		{
+			name: "count",
+			descriptor: metricdata.Descriptor{
+				Name:        "count",
+				Description: "int64 measure",
+				Unit:        metricdata.UnitBytes,
+				Type:        metricdata.TypeCumulativeInt64,
+				LabelKeys:   []string{"k1", "k2"},
+			},
+			labels: []metricdata.LabelValue{metricdata.NewLabelValue("v1"), {}},
+			value:  int64(2),
+		},
+		{
+			name: "sum",
+			descriptor: metricdata.Descriptor{
+				Name:        "sum",
+				Description: "int64 measure",
+				Unit:        metricdata.UnitBytes,
+				Type:        metricdata.TypeCumulativeInt64,
+				LabelKeys:   []string{},
+			},
+			labels: []metricdata.LabelValue{},
+			value:  int64(7),
+		},
+		{
+			name: "lastvalue",
+			descriptor: metricdata.Descriptor{
+				Name:        "lastvalue",
+				Description: "float64 measure",
+				Unit:        metricdata.UnitMilliseconds,
+				Type:        metricdata.TypeGaugeFloat64,
+				LabelKeys:   []string{},
+			},
+			labels: []metricdata.LabelValue{},
+			value:  float64(3),
+		},
+		{
+			name: "distribution",
+			descriptor: metricdata.Descriptor{
+				Name:        "distribution",
+				Description: "float64 measure",
+				Unit:        metricdata.UnitMilliseconds,
+				Type:        metricdata.TypeCumulativeDistribution,
+				LabelKeys:   []string{},
+			},
+			labels: []metricdata.LabelValue{},
+			value: &metricdata.Distribution{
+				Count:                 2,
+				Sum:                   4,
+				SumOfSquaredDeviation: 2,
+				BucketOptions:         &metricdata.BucketOptions{Bounds: []float64{2}},
+				Buckets:               []metricdata.Bucket{{Count: 1}, {Count: 1}},
+			},
+		},
+		{
+			name: "exponential",
+			descriptor: metricdata.Descriptor{
+				Name:        "exponential",
+				Description: "float64 measure",
+				Unit:        metricdata.UnitMilliseconds,
+				Type:        metricdata.TypeCumulativeDistribution,
+				LabelKeys:   []string{},
+			},
+			labels: []metricdata.LabelValue{},
+			value: &metricdata.Distribution{
+				Count:                 2,
+				Sum:                   4,
+				SumOfSquaredDeviation: 2,
+				BucketOptions:         &metricdata.BucketOptions{Bounds: []float64{1, 2, 4}},
+				Buckets:               []metricdata.Bucket{{Count: 0}, {Count: 1}, {Count: 1}, {Count: 0}},
+			},
+		},
+		{
+			name: "quantiles",
+			descriptor: metricdata.Descriptor{
+				Name:        "quantiles",
+				Description: "float64 measure",
+				Unit:        metricdata.UnitMilliseconds,
+				Type:        metricdata.TypeSummary,
+				LabelKeys:   []string{},
+			},
+			labels: []metricdata.LabelValue{},
+			value: &metricdata.Summary{
+				Count:          2,
+				Sum:            4,
+				HasCountAndSum: true,
+				Snapshot: metricdata.Snapshot{
+					Count:       2,
+					Sum:         4,
+					Percentiles: map[float64]float64{50: 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		m := metrics[tt.name]
+		if m == nil {
+			t.Errorf("%s: no metric", tt.name)
+			continue
+		}
+		if diff := cmp.Diff(m.Descriptor, tt.descriptor); diff != "" {
+			t.Errorf("%s: descriptor differs -got +want: %s", tt.name, diff)
+		}
+		if len(m.TimeSeries) != 1 || len(m.TimeSeries[0].Points) != 1 {
+			t.Errorf("%s: got time series %v; want a single point", tt.name, m.TimeSeries)
+			continue
+		}
+		ts := m.TimeSeries[0]
+		if diff := cmp.Diff(ts.LabelValues, tt.labels); diff != "" {
+			t.Errorf("%s: label values differ -got +want: %s", tt.name, diff)
+		}
+		if diff := cmp.Diff(ts.Points[0].Value, tt.value, cmpopts.IgnoreFields(metricdata.Bucket{}, "Exemplar")); diff != "" {
+			t.Errorf("%s: value differs -got +want: %s", tt.name, diff)
+		}
+		isGauge := tt.descriptor.Type == metricdata.TypeGaugeFloat64
+		if ts.StartTime.IsZero() != isGauge {
+			t.Errorf("%s: start time = %v", tt.name, ts.StartTime)
+		}
+	}
+}
+
+func TestMetricProducerExemplars(t *testing.T) {
+	restart()
+	m := stats.Float64("TestMetricProducerExemplars/m", "", stats.UnitDimensionless)
+	views := []*View{
+		{Name: "sum", Measure: m, Aggregation: Sum(), ExemplarPolicy: exemplar.Latest()},
+		{Name: "lastvalue", Measure: m, Aggregation: LastValue(), ExemplarPolicy: exemplar.Latest()},
+		{Name: "distribution", Measure: m, Aggregation: Distribution(2), ExemplarPolicy: exemplar.Reservoir(2)},
+	}
+	if err := Register(views...); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(views...)
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		err := stats.RecordWithOptions(ctx,
+			stats.WithMeasurements(m.M(1)),
+			stats.WithAttachments(map[string]string{"id": id}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	metrics := make(map[string]*metricdata.Metric)
+	for _, m := range MetricProducer().Read() {
+		metrics[m.Descriptor.Name] = m
+	}
+	for _, name := range []string{"sum", "lastvalue"} {
+		p := metrics[name].TimeSeries[0].Points[0]
+		if len(p.Exemplars) != 1 || p.Exemplars[0].Attachments["id"] != "c" {
+			t.Errorf("%s: exemplars = %v; want the last one", name, p.Exemplars)
+		}
+	}
+	d := metrics["distribution"].TimeSeries[0].Points[0].Value.(*metricdata.Distribution)
+	if b := d.Buckets[0]; len(b.Exemplars) != 2 || b.Exemplar != b.Exemplars[1] {
+		t.Errorf("distribution: bucket = %+v; want 2 exemplars", b)
+	}
+}
@@ -16,7 +16,12 @@
 package view
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opencensus.io/stats"
@@ -28,6 +33,8 @@ func init() {
 	defaultWorker = newWorker()
 	go defaultWorker.start()
 	internal.DefaultRecorder = record
+	internal.DefaultBinder = bind
+	internal.RecorderBinder = bindTo
 }
 
 type measureRef struct {
@@ -36,47 +43,166 @@ type measureRef struct {
 }
 
 type worker struct {
+	closed uint32 // closed is 1 once the worker is shut down; accessed atomically.
+
+	stopMu  sync.RWMutex // guards stopped and the sends to c
+	stopped bool
+
 	measures   map[string]*measureRef
 	views      map[string]*viewInternal
 	startTimes map[*viewInternal]time.Time
+	// viewsGen changes each time a view is registered or unregistered.
+	viewsGen uint64
+
+	rows            rowLimit
+	overflowHandler func([]OverflowReport)
+
+	exportersMu sync.RWMutex // guards exporters
+	exporters   map[Exporter]exporterOptions
+	// nextReports holds the time of the next report of the exporters with
+	// their own reporting period, and reportTimer fires at the earliest.
+	nextReports map[Exporter]time.Time
+	reportTimer *time.Timer
+
+	timer *time.Ticker
+	// now returns the time samples are aggregated at, used to expire rows.
+	now func() time.Time
 
-	timer      *time.Ticker
 	c          chan command
-	quit, done chan bool
+	quit, done chan struct{} // quit and done are closed to stop the worker and once it stopped.
 }
 
 var defaultWorker *worker
 
+var errStopped = errors.New("view: the views are shut down")
+
 var defaultReportingDuration = 10 * time.Second
 
 // Find returns a registered view associated with this name.
 // If no registered view is found, nil is returned.
 func Find(name string) (v *View) {
+	return defaultWorker.find(name)
+}
+
+// Register begins collecting data for the given views.
+// Once a view is registered, it reports data to the registered exporters.
+func Register(views ...*View) error {
+	return defaultWorker.register(views...)
+}
+
+// Unregister the given views. Data will not longer be exported for these views
+// after Unregister returns.
+// It is not necessary to unregister from views you expect to collect for the
+// duration of your program execution.
+func Unregister(views ...*View) {
+	defaultWorker.unregister(views...)
+}
+
+// RegisteredViews returns the registered views, sorted by name.
+func RegisteredViews() []ViewInfo {
+	return defaultWorker.registeredViews()
+}
+
+// RetrieveData gets a snapshot of the data collected for the the view registered
+// with the given name. It is intended for testing only.
+func RetrieveData(viewName string) ([]*Row, error) {
+	return defaultWorker.retrieveData(viewName)
+}
+
+func record(tags *tag.Map, ms interface{}, attachments map[string]string, t time.Time) {
+	defaultWorker.recordMeasurements(tags, ms, attachments, t)
+}
+
+func bind(tags *tag.Map, m interface{}) func(float64, map[string]string) {
+	b := newBinding(tags, m)
+	return func(v float64, attachments map[string]string) {
+		defaultWorker.record(b.recordReq(v, attachments))
+	}
+}
+
+// bindTo binds m to tags for recording to r if r is a Meter, and returns nil
+// otherwise.
+func bindTo(r interface{}, tags *tag.Map, m interface{}) func(float64, map[string]string) {
+	meter, ok := r.(*Meter)
+	if !ok {
+		return nil
+	}
+	b := newBinding(tags, m)
+	return func(v float64, attachments map[string]string) {
+		meter.w.record(b.recordReq(v, attachments))
+	}
+}
+
+// SetReportingPeriod sets the interval between reporting aggregated views in
+// the program. If duration is less than or equal to zero, it enables the
+// default behavior.
+//
+// Note: each exporter makes different promises about what the lowest supported
+// duration is. For example, the Stackdriver exporter recommends a value no
+// lower than 1 minute. Consult each exporter per your needs.
+func SetReportingPeriod(d time.Duration) {
+	defaultWorker.setReportingPeriod(d)
+}
+
+// SetMaxRows sets the maximum number of rows of all the registered views
+// together. Once it is reached, samples that would create a new row are folded
+// into the overflow row of their view. The overflow rows are not limited by
+// n, so each view can hold one row beyond it. If n is less than or equal to
+// zero, the number of rows is only limited by the MaxRows of each view.
+func SetMaxRows(n int) {
+	defaultWorker.setMaxRows(n)
+}
+
+// SetOverflowHandler sets a function called after each reporting period with
+// the views that folded samples into their overflow row during the period.
+// The function is called from the goroutine aggregating the views and should
+// return quickly. Passing nil removes the handler.
+func SetOverflowHandler(h func([]OverflowReport)) {
+	defaultWorker.setOverflowHandler(h)
+}
+
+// Flush reports the data of all the registered views to all the registered
+// exporters, including the measurements recorded before Flush is called,
+// and calls Flush on the exporters implementing Flusher. It returns once
+// the exporters returned.
+func Flush() {
+	defaultWorker.flush()
+}
+
+// Shutdown flushes the registered views and exporters like Flush and stops
+// collecting data. Measurements recorded after Shutdown is called are
+// dropped, and the other functions of the package do nothing once the data
+// is flushed; Register and RetrieveData return an error. If ctx is done
+// before flushing completes, Shutdown returns ctx.Err() while flushing
+// continues in the background.
+func Shutdown(ctx context.Context) error {
+	return defaultWorker.shutdown(ctx)
+}
+
+func (w *worker) find(name string) *View {
 	req := &getViewByNameReq{
 		name: name,
 		c:    make(chan *getViewByNameResp),
 	}
-	defaultWorker.c <- req
+	if !w.send(req) {
+		return nil
+	}
 	resp := <-req.c
 	return resp.v
 }
 
-// Register begins collecting data for the given views.
-// Once a view is registered, it reports data to the registered exporters.
-func Register(views ...*View) error {
+func (w *worker) register(views ...*View) error {
 	req := &registerViewReq{
 		views: views,
 		err:   make(chan error),
 	}
-	defaultWorker.c <- req
+	if !w.send(req) {
+		return errStopped
+	}
 	return <-req.err
 }
 
-// Unregister the given views. Data will not longer be exported for these views
-// after Unregister returns.
-// It is not necessary to unregister from views you expect to collect for the
-// duration of your program execution.
-func Unregister(views ...*View) {
+func (w *worker) unregister(views ...*View) {
 	names := make([]string, len(views))
 	for i := range views {
 		names[i] = views[i].Name
@@ -85,60 +211,137 @@ func Unregister(views ...*View) {
 		views: names,
 		done:  make(chan struct{}),
 	}
-	defaultWorker.c <- req
-	<-req.done
+	if w.send(req) {
+		<-req.done
+	}
 }
 
-// RetrieveData gets a snapshot of the data collected for the the view registered
-// with the given name. It is intended for testing only.
-func RetrieveData(viewName string) ([]*Row, error) {
+func (w *worker) registeredViews() []ViewInfo {
+	req := &registeredViewsReq{
+		c: make(chan []ViewInfo),
+	}
+	if !w.send(req) {
+		return nil
+	}
+	return <-req.c
+}
+
+func (w *worker) retrieveData(viewName string) ([]*Row, error) {
 	req := &retrieveDataReq{
 		now: time.Now(),
 		v:   viewName,
 		c:   make(chan *retrieveDataResp),
 	}
-	defaultWorker.c <- req
+	if !w.send(req) {
+		return nil, errStopped
+	}
 	resp := <-req.c
 	return resp.rows, resp.err
 }
 
-func record(tags *tag.Map, ms interface{}, attachments map[string]string) {
+func (w *worker) flush() {
+	req := &flushReq{c: make(chan bool)}
+	if w.send(req) {
+		<-req.c
+	}
+}
+
+func (w *worker) shutdown(ctx context.Context) error {
+	if !atomic.CompareAndSwapUint32(&w.closed, 0, 1) {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		w.flush()
+		w.stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *worker) recordMeasurements(tags *tag.Map, ms interface{}, attachments map[string]string, t time.Time) {
+	if t.IsZero() {
+		t = time.Now()
+	}
 	req := &recordReq{
 		tm:          tags,
 		ms:          ms.([]stats.Measurement),
 		attachments: attachments,
-		t:           time.Now(),
+		t:           t,
 	}
-	defaultWorker.c <- req
+	w.record(req)
 }
 
-// SetReportingPeriod sets the interval between reporting aggregated views in
-// the program. If duration is less than or equal to zero, it enables the
-// default behavior.
-//
-// Note: each exporter makes different promises about what the lowest supported
-// duration is. For example, the Stackdriver exporter recommends a value no
-// lower than 1 minute. Consult each exporter per your needs.
-func SetReportingPeriod(d time.Duration) {
+// record sends req to the worker. Records are dropped once the worker is
+// shut down.
+func (w *worker) record(req *recordReq) {
+	if atomic.LoadUint32(&w.closed) == 1 {
+		return
+	}
+	w.send(req)
+}
+
+// send sends cmd to the worker goroutine. It returns false without sending
+// cmd if the worker is stopped.
+func (w *worker) send(cmd command) bool {
+	w.stopMu.RLock()
+	defer w.stopMu.RUnlock()
+	if w.stopped {
+		return false
+	}
+	w.c <- cmd
+	return true
+}
+
+func (w *worker) setReportingPeriod(d time.Duration) {
 	// TODO(acetechnologist): ensure that the duration d is more than a certain
 	// value. e.g. 1s
 	req := &setReportingPeriodReq{
 		d: d,
 		c: make(chan bool),
 	}
-	defaultWorker.c <- req
-	<-req.c // don't return until the timer is set to the new duration.
+	if w.send(req) {
+		<-req.c // don't return until the timer is set to the new duration.
+	}
+}
+
+func (w *worker) setMaxRows(n int) {
+	req := &setMaxRowsReq{
+		n: n,
+		c: make(chan bool),
+	}
+	if w.send(req) {
+		<-req.c
+	}
+}
+
+func (w *worker) setOverflowHandler(h func([]OverflowReport)) {
+	req := &setOverflowHandlerReq{
+		h: h,
+		c: make(chan bool),
+	}
+	if w.send(req) {
+		<-req.c
+	}
 }
 
 func newWorker() *worker {
 	return &worker{
-		measures:   make(map[string]*measureRef),
-		views:      make(map[string]*viewInternal),
-		startTimes: make(map[*viewInternal]time.Time),
-		timer:      time.NewTicker(defaultReportingDuration),
-		c:          make(chan command, 1024),
-		quit:       make(chan bool),
-		done:       make(chan bool),
+		measures:    make(map[string]*measureRef),
+		views:       make(map[string]*viewInternal),
+		startTimes:  make(map[*viewInternal]time.Time),
+		exporters:   make(map[Exporter]exporterOptions),
+		nextReports: make(map[Exporter]time.Time),
+		timer:       time.NewTicker(defaultReportingDuration),
+		now:         time.Now,
+		c:           make(chan command, 1024),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
 	}
 }
 
@@ -149,17 +352,39 @@ func (w *worker) start() {
 			cmd.handleCommand(w)
 		case <-w.timer.C:
 			w.reportUsage(time.Now())
+		case <-w.reportTimerC():
+			w.reportDue(time.Now())
 		case <-w.quit:
 			w.timer.Stop()
-			close(w.c)
-			w.done <- true
-			return
+			if w.reportTimer != nil {
+				w.reportTimer.Stop()
+			}
+			// Handle the commands sent before the worker was stopped, so
+			// that their senders return.
+			for {
+				select {
+				case cmd := <-w.c:
+					cmd.handleCommand(w)
+				default:
+					close(w.done)
+					return
+				}
+			}
 		}
 	}
 }
 
+// stop stops the worker and returns once it stopped. It can be called more
+// than once.
 func (w *worker) stop() {
-	w.quit <- true
+	atomic.StoreUint32(&w.closed, 1)
+	w.stopMu.Lock()
+	stopped := w.stopped
+	w.stopped = true
+	w.stopMu.Unlock()
+	if !stopped {
+		close(w.quit)
+	}
 	<-w.done
 }
 
@@ -189,36 +414,180 @@ func (w *worker) tryRegisterView(v *View) (*viewInternal, error) {
 		// command is considered successful.
 		return x, nil
 	}
+	vi.limit = &w.rows
 	w.views[vi.view.Name] = vi
+	w.viewsGen++
 	ref := w.getMeasureRef(vi.view.Measure.Name())
 	ref.views[vi] = struct{}{}
 	return vi, nil
 }
 
-func (w *worker) reportView(v *viewInternal, now time.Time) {
+// reportView reports v to the exporters for which report returns true and
+// whose view filters select v.
+func (w *worker) reportView(v *viewInternal, now time.Time, report func(Exporter, exporterOptions) bool) {
 	if !v.isSubscribed() {
 		return
 	}
+	w.expireRows(v, now)
 	rows := v.collectedRows()
 	_, ok := w.startTimes[v]
 	if !ok {
 		w.startTimes[v] = now
 	}
+	end := time.Now()
 	viewData := &Data{
 		View:  v.view,
 		Start: w.startTimes[v],
-		End:   time.Now(),
+		End:   end,
 		Rows:  rows,
 	}
-	exportersMu.Lock()
-	for e := range exporters {
-		e.ExportView(viewData)
+	w.exportersMu.Lock()
+	defer w.exportersMu.Unlock()
+	for e := range v.intervals {
+		if o, ok := w.exporters[e]; !ok || o.temporality != Delta || !o.selects(v.view) {
+			delete(v.intervals, e)
+		}
+	}
+	for e := range v.expired {
+		if _, ok := w.exporters[e]; !ok {
+			delete(v.expired, e)
+		}
+	}
+	for e, o := range w.exporters {
+		if !o.selects(v.view) || !report(e, o) {
+			continue
+		}
+		if o.temporality == Delta {
+			vd := v.deltaData(e, w.startTimes[v], end)
+			vd.Expired = v.expired[e]
+			delete(v.expired, e)
+			e.ExportView(vd)
+			continue
+		}
+		vd := viewData
+		if expired := v.expired[e]; len(expired) > 0 {
+			c := *viewData
+			c.Expired = expired
+			vd = &c
+			delete(v.expired, e)
+		}
+		e.ExportView(vd)
+	}
+}
+
+// expireRows drops the expired rows of v and keeps them to be reported to
+// each of the exporters selecting v.
+func (w *worker) expireRows(v *viewInternal, now time.Time) {
+	rows := v.expireRows(now)
+	if len(rows) == 0 {
+		return
+	}
+	w.exportersMu.RLock()
+	defer w.exportersMu.RUnlock()
+	for e, o := range w.exporters {
+		if o.selects(v.view) {
+			v.expired[e] = append(v.expired[e], rows...)
+		}
 	}
-	exportersMu.Unlock()
+}
+
+// reportAll reports to all the exporters.
+func reportAll(Exporter, exporterOptions) bool {
+	return true
+}
+
+// reportPeriodic reports to the exporters using the reporting period of
+// the worker.
+func reportPeriodic(_ Exporter, o exporterOptions) bool {
+	return o.period <= 0
 }
 
 func (w *worker) reportUsage(now time.Time) {
 	for _, v := range w.views {
-		w.reportView(v, now)
+		w.reportView(v, now, reportPeriodic)
+	}
+	w.reportOverflow()
+}
+
+// reportDue reports the views to the exporters with their own reporting
+// period whose next report is due at now, and schedules their next reports.
+func (w *worker) reportDue(now time.Time) {
+	due := make(map[Exporter]bool)
+	for e, next := range w.nextReports {
+		if !next.After(now) {
+			due[e] = true
+		}
+	}
+	for _, v := range w.views {
+		w.reportView(v, now, func(e Exporter, _ exporterOptions) bool { return due[e] })
+	}
+	for e := range due {
+		delete(w.nextReports, e)
+	}
+	w.scheduleReports(now)
+}
+
+// scheduleReports sets the time of the next report of the exporters with
+// their own reporting period that have none, and sets the report timer to
+// the earliest of them.
+func (w *worker) scheduleReports(now time.Time) {
+	w.exportersMu.RLock()
+	for e := range w.nextReports {
+		if o, ok := w.exporters[e]; !ok || o.period <= 0 {
+			delete(w.nextReports, e)
+		}
+	}
+	var next time.Time
+	for e, o := range w.exporters {
+		if o.period <= 0 {
+			continue
+		}
+		t, ok := w.nextReports[e]
+		if !ok {
+			t = now.Add(o.period)
+			w.nextReports[e] = t
+		}
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	w.exportersMu.RUnlock()
+
+	if w.reportTimer != nil {
+		w.reportTimer.Stop()
+		w.reportTimer = nil
+	}
+	if !next.IsZero() {
+		w.reportTimer = time.NewTimer(next.Sub(now))
+	}
+}
+
+// reportTimerC returns the channel of the report timer, or nil if there is
+// no exporter with its own reporting period.
+func (w *worker) reportTimerC() <-chan time.Time {
+	if w.reportTimer == nil {
+		return nil
+	}
+	return w.reportTimer.C
+}
+
+// reportOverflow passes the views that folded samples into their overflow
+// row since the previous report to the overflow handler.
+func (w *worker) reportOverflow() {
+	if w.overflowHandler == nil {
+		return
+	}
+	var reports []OverflowReport
+	for _, v := range w.views {
+		if v.folded == v.reportedFolded {
+			continue
+		}
+		reports = append(reports, OverflowReport{View: v.view, Folded: v.folded - v.reportedFolded})
+		v.reportedFolded = v.folded
+	}
+	if len(reports) == 0 {
+		return
 	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].View.Name < reports[j].View.Name })
+	w.overflowHandler(reports)
 }
@@ -18,10 +18,12 @@ package view
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"go.opencensus.io/exemplar"
+	"go.opencensus.io/metric/metricdata"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/internal"
@@ -97,7 +99,7 @@ func (cmd *unregisterFromViewReq) handleCommand(w *worker) {
 		}
 
 		// Report pending data for this view before removing it.
-		w.reportView(vi, time.Now())
+		w.reportView(vi, time.Now(), reportAll)
 
 		vi.unsubscribe()
 		if !vi.isSubscribed() {
@@ -106,10 +108,29 @@ func (cmd *unregisterFromViewReq) handleCommand(w *worker) {
 			vi.clearRows()
 		}
 		delete(w.views, name)
+		w.viewsGen++
 	}
 	cmd.done <- struct{}{}
 }
 
+// registeredViewsReq is the command to describe the registered views.
+type registeredViewsReq struct {
+	c chan []ViewInfo
+}
+
+func (cmd *registeredViewsReq) handleCommand(w *worker) {
+	infos := make([]ViewInfo, 0, len(w.views))
+	for _, v := range w.views {
+		infos = append(infos, ViewInfo{
+			View:   v.view,
+			Rows:   len(v.collector.signatures),
+			Folded: v.folded,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].View.Name < infos[j].View.Name })
+	cmd.c <- infos
+}
+
 // retrieveDataReq is the command to retrieve data for a view.
 type retrieveDataReq struct {
 	now time.Time
@@ -139,6 +160,7 @@ func (cmd *retrieveDataReq) handleCommand(w *worker) {
 		}
 		return
 	}
+	w.expireRows(vi, cmd.now)
 	cmd.c <- &retrieveDataResp{
 		vi.collectedRows(),
 		nil,
@@ -152,23 +174,113 @@ type recordReq struct {
 	ms          []stats.Measurement
 	attachments map[string]string
 	t           time.Time
+
+	// binding, if set, records value with the measure and tags of the
+	// binding instead of ms and tm.
+	binding *binding
+	value   float64
+}
+
+// binding is a measure bound to a tag map. It caches the signature of the
+// row of each view the measure is recorded to. It is only accessed from
+// the worker goroutine.
+type binding struct {
+	measure string
+	tags    *tag.Map
+	gen     uint64 // gen is the viewsGen of the worker when sigs was filled.
+	sigs    map[*viewInternal]string
+}
+
+func newBinding(tags *tag.Map, m interface{}) *binding {
+	return &binding{
+		measure: m.(stats.Measure).Name(),
+		tags:    tags,
+	}
+}
+
+// recordReq returns the command recording v with the tags of b.
+func (b *binding) recordReq(v float64, attachments map[string]string) *recordReq {
+	return &recordReq{
+		binding:     b,
+		value:       v,
+		attachments: attachments,
+		t:           time.Now(),
+	}
 }
 
 func (cmd *recordReq) handleCommand(w *worker) {
+	if cmd.binding != nil {
+		cmd.recordBound(w)
+		return
+	}
+	now := w.now()
 	for _, m := range cmd.ms {
 		if (m == stats.Measurement{}) { // not registered
 			continue
 		}
 		ref := w.getMeasureRef(m.Measure().Name())
+		if len(ref.views) == 0 {
+			continue
+		}
+		// Aggregation data never modifies exemplars, so a single
+		// exemplar is shared by all the views of the measure.
+		e := &exemplar.Exemplar{
+			Value:       m.Value(),
+			Timestamp:   cmd.t,
+			Attachments: cmd.attachments,
+		}
 		for v := range ref.views {
-			e := &exemplar.Exemplar{
-				Value:       m.Value(),
-				Timestamp:   cmd.t,
-				Attachments: cmd.attachments,
-			}
-			v.addSample(cmd.tm, e)
+			v.addSample(cmd.tm, e, now)
+		}
+	}
+}
+
+func (cmd *recordReq) recordBound(w *worker) {
+	b := cmd.binding
+	ref := w.getMeasureRef(b.measure)
+	if len(ref.views) == 0 {
+		return
+	}
+	if b.sigs == nil || b.gen != w.viewsGen {
+		b.sigs = make(map[*viewInternal]string, len(ref.views))
+		b.gen = w.viewsGen
+	}
+	e := &exemplar.Exemplar{
+		Value:       cmd.value,
+		Timestamp:   cmd.t,
+		Attachments: cmd.attachments,
+	}
+	now := w.now()
+	for v := range ref.views {
+		sig, ok := b.sigs[v]
+		if !ok {
+			sig = v.signature(b.tags)
+			b.sigs[v] = sig
+		}
+		v.addSampleWithSignature(sig, e, now)
+	}
+}
+
+// readMetricsReq is the command to read the data of all the
+// registered views as metrics.
+type readMetricsReq struct {
+	now time.Time
+	c   chan []*metricdata.Metric
+}
+
+func (cmd *readMetricsReq) handleCommand(w *worker) {
+	metrics := make([]*metricdata.Metric, 0, len(w.views))
+	for _, v := range w.views {
+		if !v.isSubscribed() {
+			continue
 		}
+		if _, ok := w.startTimes[v]; !ok {
+			w.startTimes[v] = cmd.now
+		}
+		w.expireRows(v, cmd.now)
+		metrics = append(metrics, viewToMetric(v.view, v.collectedRows(), w.startTimes[v], cmd.now))
 	}
+	cmd.c <- metrics
 }
 
 // setReportingPeriodReq is the command to modify the duration between
@@ -187,3 +299,87 @@ func (cmd *setReportingPeriodReq) handleCommand(w *worker) {
 	}
 	cmd.c <- true
 }
+
+// setMaxRowsReq is the command to modify the maximum number of rows of all
+// the registered views.
+type setMaxRowsReq struct {
+	n int
+	c chan bool
+}
+
+func (cmd *setMaxRowsReq) handleCommand(w *worker) {
+	w.rows.max = cmd.n
+	cmd.c <- true
+}
+
+// setOverflowHandlerReq is the command to set the function called with the
+// views that folded samples into their overflow row.
+type setOverflowHandlerReq struct {
+	h func([]OverflowReport)
+	c chan bool
+}
+
+func (cmd *setOverflowHandlerReq) handleCommand(w *worker) {
+	w.overflowHandler = cmd.h
+	cmd.c <- true
+}
+
+// scheduleReportsReq is the command to schedule the reports of an exporter
+// registered with its own reporting period.
+type scheduleReportsReq struct {
+	e Exporter
+}
+
+func (cmd *scheduleReportsReq) handleCommand(w *worker) {
+	delete(w.nextReports, cmd.e)
+	w.scheduleReports(time.Now())
+}
+
+// flushReq is the command to report all the views to all the exporters and
+// flush the exporters.
+type flushReq struct {
+	c chan bool
+}
+
+func (cmd *flushReq) handleCommand(w *worker) {
+	now := time.Now()
+	for _, v := range w.views {
+		w.reportView(v, now, reportAll)
+	}
+	w.reportOverflow()
+
+	w.exportersMu.RLock()
+	var flushers []Flusher
+	for e := range w.exporters {
+		if f, ok := e.(Flusher); ok {
+			flushers = append(flushers, f)
+		}
+	}
+	w.exportersMu.RUnlock()
+	for _, f := range flushers {
+		f.Flush()
+	}
+	cmd.c <- true
+}
+
+// startDeltaReq is the command to start the first reporting interval of
+// an exporter receiving Delta data, so that its first report only holds
+// the data recorded after it was registered.
+type startDeltaReq struct {
+	e Exporter
+}
+
+func (cmd *startDeltaReq) handleCommand(w *worker) {
+	w.exportersMu.RLock()
+	o, ok := w.exporters[cmd.e]
+	w.exportersMu.RUnlock()
+	if !ok || o.temporality != Delta {
+		return
+	}
+	now := time.Now()
+	for _, v := range w.views {
+		if v.isSubscribed() && o.selects(v.view) {
+			v.startInterval(cmd.e, now)
+		}
+	}
+}
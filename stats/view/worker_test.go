@@ -18,10 +18,12 @@ package view
 import (
 	"context"
 	"errors"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"go.opencensus.io/exemplar"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 )
@@ -132,8 +134,8 @@ func Test_Worker_RecordFloat64(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	v1 := &View{"VF1", "desc VF1", []tag.Key{k1, k2}, m, Count()}
-	v2 := &View{"VF2", "desc VF2", []tag.Key{k1, k2}, m, Count()}
+	v1 := &View{Name: "VF1", Description: "desc VF1", TagKeys: []tag.Key{k1, k2}, Measure: m, Aggregation: Count()}
+	v2 := &View{Name: "VF2", Description: "desc VF2", TagKeys: []tag.Key{k1, k2}, Measure: m, Aggregation: Count()}
 
 	type want struct {
 		v    *View
@@ -283,6 +285,8 @@ func TestReportUsage(t *testing.T) {
 
 		time.Sleep(50 * time.Millisecond)
 
+		UnregisterExporter(e)
+
 		e.Lock()
 		count := e.count
 		e.Unlock()
@@ -379,6 +383,7 @@ func TestUnregisterReportsUsage(t *testing.T) {
 
 	e := &countExporter{}
 	RegisterExporter(e)
+	defer UnregisterExporter(e)
 
 	stats.Record(ctx, m1.M(1))
 	stats.Record(ctx, m2.M(1))
@@ -397,6 +402,721 @@ func TestUnregisterReportsUsage(t *testing.T) {
 	}
 }
 
+func TestDeltaTemporality(t *testing.T) {
+	m := stats.Int64("measure/TestDeltaTemporality", "desc", "unit")
+	w := newRegisteredWorker(t,
+		&View{Name: "delta/count", Measure: m, Aggregation: Count()},
+		&View{Name: "delta/sum", Measure: m, Aggregation: Sum()},
+		&View{Name: "delta/lastvalue", Measure: m, Aggregation: LastValue()},
+		&View{Name: "delta/distribution", Measure: m, Aggregation: Distribution(10)},
+	)
+
+	record := func(vals ...int64) {
+		for _, v := range vals {
+			(&recordReq{ms: []stats.Measurement{m.M(v)}, t: time.Now()}).handleCommand(w)
+		}
+	}
+
+	// Samples recorded before the Delta exporter is registered are not
+	// reported to it.
+	record(100)
+	registered := time.Now()
+	delta := &vdExporter{}
+	w.registerExporter(delta, WithTemporality(Delta))
+	(<-w.c).handleCommand(w)
+	cum := &vdExporter{}
+	w.registerExporter(cum)
+	report := func(e *vdExporter) map[string]*Data {
+		e.Lock()
+		defer e.Unlock()
+		vds := make(map[string]*Data)
+		for _, vd := range e.vds {
+			vds[vd.View.Name] = vd
+		}
+		e.vds = nil
+		return vds
+	}
+
+	record(1, 2, 3)
+	w.reportUsage(time.Now())
+	first := report(delta)
+	if got, want := first["delta/count"].Rows[0].Data, (&CountData{Value: 3}); !got.equal(want) {
+		t.Errorf("first delta count = %v; want %v", got, want)
+	}
+	if start := first["delta/count"].Start; start.Before(registered) {
+		t.Errorf("first delta start = %v; want after the exporter was registered at %v", start, registered)
+	}
+	report(cum)
+	record(4, 5)
+	w.reportUsage(time.Now())
+	second := report(delta)
+	cumulative := report(cum)
+	w.reportUsage(time.Now())
+	third := report(delta)
+
+	wantDist := newDistributionData([]float64{10})
+	wantDist.addSample(&exemplar.Exemplar{Value: 4})
+	wantDist.addSample(&exemplar.Exemplar{Value: 5})
+	wantDist.Min, wantDist.Max = 1, 100 // Min and Max are cumulative.
+	wantCumDist := newDistributionData([]float64{10})
+	for _, v := range []float64{100, 1, 2, 3, 4, 5} {
+		wantCumDist.addSample(&exemplar.Exemplar{Value: v})
+	}
+	wantDelta := map[string]AggregationData{
+		"delta/count":        &CountData{Value: 2},
+		"delta/sum":          &SumData{Value: 9},
+		"delta/lastvalue":    &LastValueData{Value: 5},
+		"delta/distribution": wantDist,
+	}
+	wantCumulative := map[string]AggregationData{
+		"delta/count":        &CountData{Value: 6},
+		"delta/sum":          &SumData{Value: 115},
+		"delta/lastvalue":    &LastValueData{Value: 5},
+		"delta/distribution": wantCumDist,
+	}
+	for name, want := range wantDelta {
+		vd := second[name]
+		if vd == nil || len(vd.Rows) != 1 {
+			t.Fatalf("%s: delta data = %v; want a single row", name, vd)
+		}
+		if got := vd.Rows[0].Data; !got.equal(want) {
+			t.Errorf("%s: delta data = %v; want %v", name, got, want)
+		}
+		if !vd.Start.Equal(first[name].End) {
+			t.Errorf("%s: delta start = %v; want end of previous report %v", name, vd.Start, first[name].End)
+		}
+		if got, want := cumulative[name].Rows[0].Data, wantCumulative[name]; !got.equal(want) {
+			t.Errorf("%s: cumulative data = %v; want %v", name, got, want)
+		}
+	}
+
+	// Rows without samples in the interval are omitted, except for gauges.
+	for name := range wantDelta {
+		wantRows := 0
+		if name == "delta/lastvalue" {
+			wantRows = 1
+		}
+		if got := len(third[name].Rows); got != wantRows {
+			t.Errorf("%s: got %d rows for an interval without samples; want %d", name, got, wantRows)
+		}
+	}
+}
+
+func TestRowLimits(t *testing.T) {
+	k, err := tag.NewKey("path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := stats.Int64("measure/TestRowLimits", "desc", "unit")
+	limited := &View{Name: "limited", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Count(), MaxRows: 2}
+	unlimited := &View{Name: "unlimited", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Count()}
+	w := newRegisteredWorker(t, limited, unlimited)
+	(&setMaxRowsReq{n: 4, c: make(chan bool, 1)}).handleCommand(w)
+	var reports []OverflowReport
+	(&setOverflowHandlerReq{
+		h: func(r []OverflowReport) { reports = append(reports, r...) },
+		c: make(chan bool, 1),
+	}).handleCommand(w)
+
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/a", "/e"} {
+		ctx, err := tag.New(context.Background(), tag.Insert(k, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		(&recordReq{tm: tag.FromContext(ctx), ms: []stats.Measurement{m.M(1)}, t: time.Now()}).handleCommand(w)
+	}
+
+	count := func(name string) map[string]int64 {
+		got := make(map[string]int64)
+		for _, r := range w.views[name].collectedRows() {
+			got[r.Tags[0].Value] = r.Data.(*CountData).Value
+		}
+		return got
+	}
+	// The limited view keeps /a and /b; the unlimited view is stopped by the
+	// limit of 4 rows for all the views.
+	if got, want := count("limited"), map[string]int64{"/a": 2, "/b": 1, OverflowTagValue: 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("limited rows = %v; want %v", got, want)
+	}
+	if got, want := count("unlimited"), map[string]int64{"/a": 2, "/b": 1, OverflowTagValue: 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unlimited rows = %v; want %v", got, want)
+	}
+
+	w.reportUsage(time.Now())
+	want := []OverflowReport{{View: limited, Folded: 3}, {View: unlimited, Folded: 3}}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("overflow reports = %v; want %v", reports, want)
+	}
+	reports = nil
+	w.reportUsage(time.Now())
+	if len(reports) != 0 {
+		t.Errorf("got overflow reports %v for a period without folded samples", reports)
+	}
+
+	// Reports count the samples folded during their period only.
+	ctx, err := tag.New(context.Background(), tag.Insert(k, "/f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	(&recordReq{tm: tag.FromContext(ctx), ms: []stats.Measurement{m.M(1)}, t: time.Now()}).handleCommand(w)
+	w.reportUsage(time.Now())
+	want = []OverflowReport{{View: limited, Folded: 1}, {View: unlimited, Folded: 1}}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("overflow reports = %v; want %v", reports, want)
+	}
+	// The overflow rows exceed the limit of 4 rows.
+	if got, want := w.rows.rows, 6; got != want {
+		t.Errorf("rows = %d; want %d", got, want)
+	}
+
+	// Unregistering a view frees its rows.
+	unreg := &unregisterFromViewReq{views: []string{"limited"}, done: make(chan struct{}, 1)}
+	unreg.handleCommand(w)
+	if got, want := w.rows.rows, 3; got != want {
+		t.Errorf("rows after unregister = %d; want %d", got, want)
+	}
+}
+
+func TestRowTTL(t *testing.T) {
+	k, err := tag.NewKey("pod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := stats.Int64("measure/TestRowTTL", "desc", "unit")
+	w := newRegisteredWorker(t, &View{Name: "ttl", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Count(), RowTTL: time.Minute})
+	e := &vdExporter{}
+	w.registerExporter(e)
+
+	start := time.Now()
+	record := func(pod string, at time.Time) {
+		ctx, err := tag.New(context.Background(), tag.Insert(k, pod))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.now = func() time.Time { return at }
+		(&recordReq{tm: tag.FromContext(ctx), ms: []stats.Measurement{m.M(1)}, t: at}).handleCommand(w)
+	}
+	record("a", start)
+	record("b", start)
+	record("b", start.Add(50*time.Second))
+
+	w.reportUsage(start.Add(90 * time.Second))
+	w.reportUsage(start.Add(100 * time.Second))
+	e.Lock()
+	vds := e.vds
+	e.Unlock()
+	if len(vds) != 2 {
+		t.Fatalf("got %d reports; want 2", len(vds))
+	}
+	tags := func(rows []*Row) []string {
+		var vals []string
+		for _, r := range rows {
+			vals = append(vals, r.Tags[0].Value)
+		}
+		return vals
+	}
+	if got, want := tags(vds[0].Rows), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v; want %v", got, want)
+	}
+	if got, want := tags(vds[0].Expired), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expired rows = %v; want %v", got, want)
+	}
+	if got, want := vds[0].Expired[0].Data, (&CountData{Value: 1}); !got.equal(want) {
+		t.Errorf("expired data = %v; want %v", got, want)
+	}
+	if len(vds[1].Expired) != 0 {
+		t.Errorf("expired rows reported again: %v", vds[1].Expired)
+	}
+
+	// A row recorded again after it expired starts from zero.
+	record("a", start.Add(110*time.Second))
+	rows := w.views["ttl"].collectedRows()
+	for _, r := range rows {
+		if r.Tags[0].Value == "a" && !r.Data.equal(&CountData{Value: 1}) {
+			t.Errorf("recreated row data = %v; want %v", r.Data, &CountData{Value: 1})
+		}
+	}
+}
+
+func TestRowTTLWithTime(t *testing.T) {
+	m := stats.Int64("measure/TestRowTTLWithTime", "desc", "unit")
+	w := newRegisteredWorker(t, &View{Name: "ttl_with_time", Measure: m, Aggregation: Count(), RowTTL: time.Hour})
+	e := &vdExporter{}
+	w.registerExporter(e)
+
+	// A sample recorded with a time older than the RowTTL expires from the
+	// time it is aggregated, not from its own time.
+	now := time.Now()
+	w.now = func() time.Time { return now }
+	(&recordReq{ms: []stats.Measurement{m.M(1)}, t: now.Add(-2 * time.Hour)}).handleCommand(w)
+	w.reportUsage(now.Add(time.Minute))
+	e.Lock()
+	vds := e.vds
+	e.Unlock()
+	if len(vds) != 1 {
+		t.Fatalf("got %d reports; want 1", len(vds))
+	}
+	if len(vds[0].Rows) != 1 || len(vds[0].Expired) != 0 {
+		t.Errorf("got %d rows and %d expired rows; want 1 row and none expired", len(vds[0].Rows), len(vds[0].Expired))
+	}
+}
+
+func TestRecordOrder(t *testing.T) {
+	restart()
+	m := stats.Int64("measure/TestRecordOrder", "desc", "unit")
+	v := &View{Name: "order", Measure: m, Aggregation: LastValue()}
+	if err := Register(v); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(v)
+
+	// Record more than the worker channel holds, so that recording also
+	// blocks on the worker.
+	n := 4 * cap(defaultWorker.c)
+	for i := 1; i <= n; i++ {
+		stats.Record(context.Background(), m.M(int64(i)))
+	}
+	rows, err := RetrieveData(v.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows; want 1", len(rows))
+	}
+	if got, want := rows[0].Data, (&LastValueData{Value: float64(n)}); !got.equal(want) {
+		t.Errorf("last value = %v; want %v", got, want)
+	}
+}
+
+func TestBoundMeasure(t *testing.T) {
+	restart()
+	k, err := tag.NewKey("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := stats.Int64("measure/TestBoundMeasure", "desc", "unit")
+	v := &View{Name: "bound", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Distribution(10)}
+	if err := Register(v); err != nil {
+		t.Fatal(err)
+	}
+
+	bindCtx, err := tag.New(context.Background(), tag.Insert(k, "bound"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordCtx, err := tag.New(context.Background(), tag.Insert(k, "ignored"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := m.Bind(bindCtx)
+	b.Record(recordCtx, 1)
+	b.Record(recordCtx, 20)
+	stats.Record(bindCtx, m.M(3))
+
+	rows, err := RetrieveData(v.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows; want 1", len(rows))
+	}
+	if got, want := rows[0].Tags, []tag.Tag{{Key: k, Value: "bound"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v; want %v", got, want)
+	}
+	d := rows[0].Data.(*DistributionData)
+	if got, want := d.CountPerBucket, []int64{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("counts per bucket = %v; want %v", got, want)
+	}
+	if got, want := d.ExemplarsPerBucket[1].Attachments["tag:k"], "bound"; got != want {
+		t.Errorf("exemplar attachment = %q; want %q", got, want)
+	}
+
+	// Bindings survive the views of the measure changing.
+	Unregister(v)
+	b.Record(recordCtx, 1)
+	if err := Register(v); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(v)
+	b.Record(recordCtx, 1)
+	rows, err = RetrieveData(v.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Data.(*DistributionData).Count != 1 {
+		t.Errorf("rows after registering again = %v; want a single sample", rows)
+	}
+}
+
+func TestBoundMeasureTo(t *testing.T) {
+	restart()
+	k, err := tag.NewKey("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := stats.Int64("measure/TestBoundMeasureTo", "desc", "unit")
+	v := &View{Name: "bound_to", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Count()}
+	if err := Register(v); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(v)
+	meter := NewMeter()
+	defer meter.Stop()
+	if err := meter.Register(v); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := tag.New(context.Background(), tag.Insert(k, "bound"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := m.BindTo(ctx, meter)
+	b.Record(context.Background(), 1)
+	b.Record(context.Background(), 1)
+
+	rows, err := meter.RetrieveData(v.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || !rows[0].Data.equal(&CountData{Value: 2}) {
+		t.Errorf("meter rows = %v; want a single row counting 2", rows)
+	}
+	if got, want := rows[0].Tags, []tag.Tag{{Key: k, Value: "bound"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v; want %v", got, want)
+	}
+	if rows, _ := RetrieveData(v.Name); len(rows) != 0 {
+		t.Errorf("default meter rows = %v; want none", rows)
+	}
+
+	// Recorders other than a Meter record each value with the bound tags.
+	r := &tagsRecorder{}
+	m.BindTo(ctx, r).Record(context.Background(), 1)
+	if got, want := r.values, []string{"bound"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded tag values = %v; want %v", got, want)
+	}
+}
+
+// tagsRecorder records the value of the tag k of each record.
+type tagsRecorder struct {
+	values []string
+}
+
+func (r *tagsRecorder) Record(tags *tag.Map, _ interface{}, _ map[string]string, _ time.Time) {
+	k, _ := tag.NewKey("k")
+	v, _ := tags.Value(k)
+	r.values = append(r.values, v)
+}
+
+func TestMeter(t *testing.T) {
+	restart()
+	m := stats.Int64("measure/TestMeter", "desc", "unit")
+	v := &View{Name: "meter", Measure: m, Aggregation: Count()}
+
+	meter := NewMeter()
+	defer meter.Stop()
+	if err := meter.Register(v); err != nil {
+		t.Fatal(err)
+	}
+	if Find(v.Name) != nil {
+		t.Errorf("view registered with a meter is found in the default meter")
+	}
+	if got := meter.Find(v.Name); got != v {
+		t.Errorf("meter.Find(%q) = %v; want %v", v.Name, got, v)
+	}
+	e := &vdExporter{}
+	meter.RegisterExporter(e)
+	meter.SetReportingPeriod(10 * time.Millisecond)
+
+	stats.RecordTo(context.Background(), meter, m.M(1), m.M(1))
+	stats.Record(context.Background(), m.M(1))
+
+	rows, err := meter.RetrieveData(v.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || !rows[0].Data.equal(&CountData{Value: 2}) {
+		t.Errorf("meter rows = %v; want a count of 2", rows)
+	}
+	if _, err := RetrieveData(v.Name); err == nil {
+		t.Errorf("default meter retrieved data for a view it does not have")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		e.Lock()
+		n := len(e.vds)
+		e.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the meter did not report to its exporter")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := len(meter.MetricProducer().Read()); got != 1 {
+		t.Errorf("got %d metrics from the meter; want 1", got)
+	}
+}
+
+func TestExporterViewFilter(t *testing.T) {
+	m := stats.Int64("measure/TestExporterViewFilter", "desc", "unit")
+	w := newRegisteredWorker(t,
+		&View{Name: "filter/debug", Measure: m, Aggregation: Count()},
+		&View{Name: "filter/kpi", Measure: m, Aggregation: Sum()},
+		&View{Name: "filter/other", Measure: m, Aggregation: LastValue()},
+	)
+	byName := &vdExporter{}
+	w.registerExporter(byName, WithViews("filter/debug"))
+	byFilter := &vdExporter{}
+	w.registerExporter(byFilter, WithViewFilter(func(v *View) bool {
+		return v.Aggregation.Type != AggTypeCount
+	}))
+	all := &vdExporter{}
+	w.registerExporter(all)
+
+	w.reportUsage(time.Now())
+	names := func(e *vdExporter) map[string]bool {
+		e.Lock()
+		defer e.Unlock()
+		got := make(map[string]bool)
+		for _, vd := range e.vds {
+			got[vd.View.Name] = true
+		}
+		return got
+	}
+	tests := []struct {
+		name string
+		e    *vdExporter
+		want map[string]bool
+	}{
+		{"WithViews", byName, map[string]bool{"filter/debug": true}},
+		{"WithViewFilter", byFilter, map[string]bool{"filter/kpi": true, "filter/other": true}},
+		{"all", all, map[string]bool{"filter/debug": true, "filter/kpi": true, "filter/other": true}},
+	}
+	for _, tt := range tests {
+		if got := names(tt.e); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: reported views = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExporterReportingPeriod(t *testing.T) {
+	meter := NewMeter()
+	defer meter.Stop()
+	meter.SetReportingPeriod(time.Hour)
+	m := stats.Int64("measure/TestExporterReportingPeriod", "desc", "unit")
+	v := &View{Name: "period", Measure: m, Aggregation: Count()}
+	if err := meter.Register(v); err != nil {
+		t.Fatal(err)
+	}
+	fast := &vdExporter{}
+	meter.RegisterExporter(fast, WithReportingPeriod(10*time.Millisecond))
+	slow := &vdExporter{}
+	meter.RegisterExporter(slow)
+
+	count := func(e *vdExporter) int {
+		e.Lock()
+		defer e.Unlock()
+		return len(e.vds)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for count(fast) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d reports with a 10ms period; want at least 3", count(fast))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := count(slow); got != 0 {
+		t.Errorf("got %d reports with a period of an hour; want 0", got)
+	}
+}
+
+func TestFlushAndShutdown(t *testing.T) {
+	meter := NewMeter()
+	meter.SetReportingPeriod(time.Hour)
+	m := stats.Int64("measure/TestFlushAndShutdown", "desc", "unit")
+	v := &View{Name: "flush", Measure: m, Aggregation: Count()}
+	if err := meter.Register(v); err != nil {
+		t.Fatal(err)
+	}
+	e := &flushExporter{}
+	meter.RegisterExporter(e)
+
+	stats.RecordTo(context.Background(), meter, m.M(1))
+	meter.Flush()
+	if got, want := e.counts(), []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("counts after Flush = %v; want %v", got, want)
+	}
+	if e.flushes != 1 {
+		t.Errorf("got %d exporter flushes after Flush; want 1", e.flushes)
+	}
+
+	stats.RecordTo(context.Background(), meter, m.M(1))
+	if err := meter.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.counts(), []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("counts after Shutdown = %v; want %v", got, want)
+	}
+	if e.flushes != 2 {
+		t.Errorf("got %d exporter flushes after Shutdown; want 2", e.flushes)
+	}
+
+	// Recording after Shutdown is a no-op.
+	stats.RecordTo(context.Background(), meter, m.M(1))
+	if err := meter.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown: %v", err)
+	}
+}
+
+func TestStopAfterShutdown(t *testing.T) {
+	meter := NewMeter()
+	defer meter.Stop()
+	if err := meter.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	meter.Stop()
+}
+
+func TestUseAfterShutdown(t *testing.T) {
+	meter := NewMeter()
+	m := stats.Int64("measure/TestUseAfterShutdown", "desc", "unit")
+	v := &View{Name: "after_shutdown", Measure: m, Aggregation: Count()}
+	e := &flushExporter{}
+	meter.RegisterExporter(e)
+	if err := meter.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	meter.Flush()
+	if e.flushes != 1 {
+		t.Errorf("got %d exporter flushes; want 1, from Shutdown only", e.flushes)
+	}
+	if err := meter.Register(v); err == nil {
+		t.Error("Register after Shutdown succeeded; want an error")
+	}
+	if got := meter.Find(v.Name); got != nil {
+		t.Errorf("Find after Shutdown = %v; want nil", got)
+	}
+	if _, err := meter.RetrieveData(v.Name); err == nil {
+		t.Error("RetrieveData after Shutdown succeeded; want an error")
+	}
+	meter.SetReportingPeriod(time.Second)
+	meter.Unregister(v)
+	meter.RegisterExporter(&vdExporter{})
+	stats.RecordTo(context.Background(), meter, m.M(1))
+}
+
+type flushExporter struct {
+	vdExporter
+	flushes int
+}
+
+func (e *flushExporter) Flush() {
+	e.Lock()
+	defer e.Unlock()
+	e.flushes++
+}
+
+func (e *flushExporter) counts() []int64 {
+	e.Lock()
+	defer e.Unlock()
+	var counts []int64
+	for _, vd := range e.vds {
+		for _, r := range vd.Rows {
+			counts = append(counts, r.Data.(*CountData).Value)
+		}
+	}
+	return counts
+}
+
+func TestRecordWithOptions(t *testing.T) {
+	k, err := tag.NewKey("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := stats.Int64("measure/TestRecordWithOptions", "desc", "unit")
+	v := &View{Name: "options", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Distribution(10)}
+	at := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	restart()
+	meter := NewMeter()
+	defer meter.Stop()
+	for _, r := range []interface {
+		Register(...*View) error
+		RetrieveData(string) ([]*Row, error)
+	}{defaultMeter{}, meter} {
+		if err := r.Register(v); err != nil {
+			t.Fatal(err)
+		}
+		opts := []stats.Options{
+			stats.WithTags(tag.Upsert(k, "v")),
+			stats.WithAttachments(map[string]string{"id": "42"}),
+			stats.WithMeasurements(m.M(1)),
+			stats.WithTime(at),
+		}
+		if r == meter {
+			opts = append(opts, stats.WithRecorder(meter))
+		}
+		if err := stats.RecordWithOptions(context.Background(), opts...); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := r.RetrieveData(v.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("%T: got %d rows; want 1", r, len(rows))
+		}
+		if got, want := rows[0].Tags, []tag.Tag{{Key: k, Value: "v"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%T: tags = %v; want %v", r, got, want)
+		}
+		e := rows[0].Data.(*DistributionData).ExemplarsPerBucket[0]
+		if e == nil || e.Attachments["id"] != "42" || e.Attachments["tag:k"] != "v" || !e.Timestamp.Equal(at) {
+			t.Errorf("%T: exemplar = %+v; want the attachments and time of the options", r, e)
+		}
+	}
+}
+
+// defaultMeter uses the package functions.
+type defaultMeter struct{}
+
+func (defaultMeter) Register(views ...*View) error { return Register(views...) }
+
+func (defaultMeter) RetrieveData(name string) ([]*Row, error) { return RetrieveData(name) }
+
+func TestRegisteredViews(t *testing.T) {
+	meter := NewMeter()
+	defer meter.Stop()
+	k, err := tag.NewKey("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := stats.Int64("measure/TestRegisteredViews", "desc", "unit")
+	count := &View{Name: "registered/count", Measure: m, TagKeys: []tag.Key{k}, Aggregation: Count(), MaxRows: 1}
+	sum := &View{Name: "registered/sum", Measure: m, Aggregation: Sum()}
+	if err := meter.Register(sum, count); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"a", "b", "c"} {
+		ctx, err := tag.New(context.Background(), tag.Insert(k, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats.RecordTo(ctx, meter, m.M(1))
+	}
+
+	got := meter.RegisteredViews()
+	want := []ViewInfo{
+		{View: count, Rows: 2, Folded: 2},
+		{View: sum, Rows: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RegisteredViews() = %+v; want %+v", got, want)
+	}
+}
+
 type countExporter struct {
 	sync.Mutex
 	count      int64
@@ -428,6 +1148,19 @@ func (e *vdExporter) ExportView(vd *Data) {
 }
 
 // restart stops the current processors and creates a new one.
+// newRegisteredWorker returns a worker that is not started, with views
+// registered. Tests drive it by handling commands directly.
+func newRegisteredWorker(t *testing.T, views ...*View) *worker {
+	t.Helper()
+	w := newWorker()
+	reg := &registerViewReq{views: views, err: make(chan error, 1)}
+	reg.handleCommand(w)
+	if err := <-reg.err; err != nil {
+		t.Fatalf("cannot register: %v", err)
+	}
+	return w
+}
+
 func restart() {
 	defaultWorker.stop()
 	defaultWorker = newWorker()
@@ -61,7 +61,7 @@ func extractTagsAttachments(ctx context.Context, a exemplar.Attachments) exempla
 	}
 
 	for k, v := range m.m {
-		a[exemplar.KeyPrefixTag+k.Name()] = v
+		a[exemplar.KeyPrefixTag+k.Name()] = v.value
 	}
 	return a
 }
@@ -22,5 +22,7 @@ the OpenCensus instrumentation data.
 Tags can be propagated on the wire and in the same
 process via context.Context. Encode and Decode should be
 used to represent tags into their binary propagation form.
+Tags inserted with the WithTTL(TTLNoPropagation) metadata
+are only propagated in the same process.
 */
 package tag // import "go.opencensus.io/tag"
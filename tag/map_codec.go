@@ -160,7 +160,8 @@ func (eg *encoderGRPC) bytes() []byte {
 }
 
 // Encode encodes the tag map into a []byte. It is useful to propagate
-// the tag maps on wire in binary format.
+// the tag maps on wire in binary format. Tags with TTLNoPropagation
+// are not encoded.
 func Encode(m *Map) []byte {
 	if m == nil {
 		return nil
@@ -170,17 +171,24 @@ func Encode(m *Map) []byte {
 	}
 	eg.writeByte(byte(tagsVersionID))
 	for k, v := range m.m {
+		if !v.m.propagates() {
+			continue
+		}
 		eg.writeByte(byte(keyTypeString))
 		eg.writeStringWithVarintLen(k.name)
-		eg.writeBytesWithVarintLen([]byte(v))
+		eg.writeBytesWithVarintLen([]byte(v.value))
 	}
 	return eg.bytes()
 }
 
 // Decode decodes the given []byte into a tag map.
+// The decoded tags have TTLUnlimitedPropagation.
 func Decode(bytes []byte) (*Map, error) {
 	ts := newMap()
-	err := DecodeEach(bytes, ts.upsert)
+	md := createMetadatas()
+	err := DecodeEach(bytes, func(k Key, v string) {
+		ts.upsert(k, v, md)
+	})
 	if err != nil {
 		// no partial failures
 		return nil, err
@@ -0,0 +1,152 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tag
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxBaggageMembers and maxBaggageLength are the limits the W3C
+	// Baggage standard requires propagators to support.
+	maxBaggageMembers = 180
+	maxBaggageLength  = 8192
+)
+
+var errMalformedBaggage = errors.New("malformed baggage: members must be key=value pairs; max length must be 8192 characters and max 180 members")
+
+// EncodeBaggage encodes the tag map into the value of a W3C Baggage
+// header. See https://w3c.github.io/baggage/.
+//
+// Tags are encoded in key order and their values are percent-encoded.
+// Tags with TTLNoPropagation and tags whose key names are not valid baggage
+// keys are skipped, and so are the tags that would make the encoding exceed
+// the limits of the standard.
+func EncodeBaggage(m *Map) string {
+	if m == nil {
+		return ""
+	}
+	keys := make([]Key, 0, len(m.m))
+	for k, v := range m.m {
+		if v.m.propagates() && isBaggageKey(k.name) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+
+	var b strings.Builder
+	n := 0
+	for _, k := range keys {
+		member := k.name + "=" + escapeBaggageValue(m.m[k].value)
+		l := len(member)
+		if n > 0 {
+			l++ // comma
+		}
+		if n == maxBaggageMembers || b.Len()+l > maxBaggageLength {
+			continue
+		}
+		if n > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(member)
+		n++
+	}
+	return b.String()
+}
+
+// DecodeBaggage decodes the value of a W3C Baggage header into a tag map.
+//
+// The decoded tags have TTLUnlimitedPropagation and member properties
+// are ignored. Members that are not valid tags, see
+// validation rules of NewKey and Insert, are skipped.
+// An error is returned if the value is malformed or exceeds the limits
+// of the standard.
+func DecodeBaggage(h string) (*Map, error) {
+	if len(h) > maxBaggageLength {
+		return nil, errMalformedBaggage
+	}
+	members := strings.Split(h, ",")
+	if len(members) > maxBaggageMembers {
+		return nil, errMalformedBaggage
+	}
+	m := newMap()
+	md := createMetadatas()
+	for _, member := range members {
+		if i := strings.IndexByte(member, ';'); i >= 0 {
+			member = member[:i]
+		}
+		member = trimOWS(member)
+		if member == "" {
+			continue
+		}
+		eq := strings.IndexByte(member, '=')
+		if eq < 0 {
+			return nil, errMalformedBaggage
+		}
+		name := trimOWS(member[:eq])
+		if !isBaggageKey(name) {
+			return nil, errMalformedBaggage
+		}
+		v, err := url.PathUnescape(trimOWS(member[eq+1:]))
+		if err != nil {
+			return nil, errMalformedBaggage
+		}
+		if !checkKeyName(name) || !checkValue(v) {
+			continue
+		}
+		m.upsert(Key{name: name}, v, md)
+	}
+	return m, nil
+}
+
+func trimOWS(s string) string {
+	return strings.Trim(s, " \t")
+}
+
+// isBaggageKey reports whether name is a token as defined by RFC 7230.
+func isBaggageKey(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeBaggageValue percent-encodes the characters of v that are not
+// allowed in baggage values, and the percent sign itself.
+func escapeBaggageValue(v string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c <= ' ', c >= 0x7f, c == '"', c == ',', c == ';', c == '\\', c == '%':
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
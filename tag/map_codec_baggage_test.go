@@ -0,0 +1,154 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tag
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeBaggage(t *testing.T) {
+	k1, _ := NewKey("k1")
+	k2, _ := NewKey("k2")
+	k3, _ := NewKey("k3 is not a token")
+	k4, _ := NewKey("k4")
+
+	ctx, err := New(context.Background(),
+		Upsert(k1, "v1"),
+		Upsert(k2, "v2 is very weird <>.,?/'\";:`~!@#$%^&*()_-+={[}]|\\"),
+		Upsert(k3, "v3"),
+		Upsert(k4, ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := FromContext(ctx)
+
+	got := EncodeBaggage(m)
+	want := "k1=v1,k2=v2%20is%20very%20weird%20<>.%2C?/'%22%3B:`~!@#$%25^&*()_-+={[}]|%5C,k4="
+	if got != want {
+		t.Errorf("EncodeBaggage() = %q; want %q", got, want)
+	}
+
+	decoded, err := DecodeBaggage(got)
+	if err != nil {
+		t.Fatalf("DecodeBaggage() = %v", err)
+	}
+	delete(m.m, k3)
+	if !reflect.DeepEqual(decoded, m) {
+		t.Errorf("DecodeBaggage() = %v; want %v", decoded, m)
+	}
+}
+
+func TestDecodeBaggage(t *testing.T) {
+	k1, _ := NewKey("k1")
+	k2, _ := NewKey("k2")
+	tests := []struct {
+		name    string
+		header  string
+		want    map[Key]string
+		wantErr bool
+	}{
+		{
+			name:   "whitespace and properties",
+			header: " k1 = v1 ;prop=1 ,\tk2=v%202;flag",
+			want:   map[Key]string{k1: "v1", k2: "v 2"},
+		},
+		{
+			name:   "empty members",
+			header: "k1=v1,,",
+			want:   map[Key]string{k1: "v1"},
+		},
+		{
+			name:   "value not a valid tag value",
+			header: "k1=v1,k2=%E2%82%AC",
+			want:   map[Key]string{k1: "v1"},
+		},
+		{
+			name:   "value too long",
+			header: "k1=v1,k2=" + strings.Repeat("a", maxKeyLength+1),
+			want:   map[Key]string{k1: "v1"},
+		},
+		{
+			name:    "missing equals",
+			header:  "k1=v1,k2",
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			header:  "k(1)=v1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid percent-encoding",
+			header:  "k1=%zz",
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			header:  "k1=" + strings.Repeat("a", maxBaggageLength),
+			wantErr: true,
+		},
+		{
+			name:    "too many members",
+			header:  strings.Repeat("k1=v1,", maxBaggageMembers) + "k2=v2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := DecodeBaggage(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeBaggage() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := make(map[Key]string)
+			for k, v := range m.m {
+				got[k] = v.value
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeBaggage() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeBaggageLimits(t *testing.T) {
+	var mods []Mutator
+	for i := 0; i < maxBaggageMembers+10; i++ {
+		k, _ := NewKey(fmt.Sprintf("k%03d", i))
+		mods = append(mods, Upsert(k, "v"))
+	}
+	ctx, _ := New(context.Background(), mods...)
+	h := EncodeBaggage(FromContext(ctx))
+	if got, want := strings.Count(h, ",")+1, maxBaggageMembers; got != want {
+		t.Errorf("EncodeBaggage() encoded %d members; want %d", got, want)
+	}
+
+	mods = mods[:0]
+	for i := 0; i < 40; i++ {
+		k, _ := NewKey(fmt.Sprintf("k%03d", i))
+		mods = append(mods, Upsert(k, strings.Repeat("%", maxKeyLength)))
+	}
+	ctx, _ = New(context.Background(), mods...)
+	if got := len(EncodeBaggage(FromContext(ctx))); got > maxBaggageLength {
+		t.Errorf("len(EncodeBaggage()) = %d; want <= %d", got, maxBaggageLength)
+	}
+}
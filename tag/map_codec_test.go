@@ -91,7 +91,7 @@ func TestEncodeDecode(t *testing.T) {
 
 		got := make([]keyValue, 0)
 		for k, v := range decoded.m {
-			got = append(got, keyValue{k, string(v)})
+			got = append(got, keyValue{k, string(v.value)})
 		}
 		want := tc.pairs
 
@@ -152,3 +152,28 @@ func TestDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeSkipsNoPropagation(t *testing.T) {
+	k1, _ := NewKey("k1")
+	k2, _ := NewKey("k2")
+	ctx, _ := New(context.Background(),
+		Insert(k1, "v1"),
+		Insert(k2, "v2", WithTTL(TTLNoPropagation)))
+
+	decoded, err := Decode(Encode(FromContext(ctx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := decoded.Value(k1); got != "v1" {
+		t.Errorf("decoded %s = %q; want %q", k1.Name(), got, "v1")
+	}
+	if _, ok := decoded.Value(k2); ok {
+		t.Errorf("decoded tag map contains %s; want it skipped", k2.Name())
+	}
+	if got := decoded.m[k1].m.ttl; got != TTLUnlimitedPropagation {
+		t.Errorf("decoded ttl = %v; want %v", got, TTLUnlimitedPropagation)
+	}
+	if got, want := EncodeBaggage(FromContext(ctx)), "k1=v1"; got != want {
+		t.Errorf("EncodeBaggage() = %q; want %q", got, want)
+	}
+}
@@ -34,8 +34,8 @@ func TestContext(t *testing.T) {
 	)
 	got := FromContext(ctx)
 	want := newMap()
-	want.insert(k1, "v1")
-	want.insert(k2, "v2")
+	want.insert(k1, "v1", createMetadatas())
+	want.insert(k2, "v2", createMetadatas())
 
 	if !reflect.DeepEqual(got, want) {
 		t.Errorf("Map = %#v; want %#v", got, want)
@@ -52,8 +52,8 @@ func TestDo(t *testing.T) {
 	)
 	got := FromContext(ctx)
 	want := newMap()
-	want.insert(k1, "v1")
-	want.insert(k2, "v2")
+	want.insert(k1, "v1", createMetadatas())
+	want.insert(k2, "v2", createMetadatas())
 	Do(ctx, func(ctx context.Context) {
 		got = FromContext(ctx)
 	})
@@ -174,17 +174,17 @@ func TestNewValidation(t *testing.T) {
 		seed *Map
 	}{
 		// Key name validation in seed
-		{err: "invalid key", seed: &Map{m: map[Key]string{{name: ""}: "foo"}}},
-		{err: "", seed: &Map{m: map[Key]string{{name: "key"}: "foo"}}},
-		{err: "", seed: &Map{m: map[Key]string{{name: strings.Repeat("a", 255)}: "census"}}},
-		{err: "invalid key", seed: &Map{m: map[Key]string{{name: strings.Repeat("a", 256)}: "census"}}},
-		{err: "invalid key", seed: &Map{m: map[Key]string{{name: "Приве́т"}: "census"}}},
+		{err: "invalid key", seed: &Map{m: map[Key]tagContent{{name: ""}: {value: "foo", m: createMetadatas()}}}},
+		{err: "", seed: &Map{m: map[Key]tagContent{{name: "key"}: {value: "foo", m: createMetadatas()}}}},
+		{err: "", seed: &Map{m: map[Key]tagContent{{name: strings.Repeat("a", 255)}: {value: "census", m: createMetadatas()}}}},
+		{err: "invalid key", seed: &Map{m: map[Key]tagContent{{name: strings.Repeat("a", 256)}: {value: "census", m: createMetadatas()}}}},
+		{err: "invalid key", seed: &Map{m: map[Key]tagContent{{name: "Приве́т"}: {value: "census", m: createMetadatas()}}}},
 
 		// Value validation
-		{err: "", seed: &Map{m: map[Key]string{{name: "key"}: ""}}},
-		{err: "", seed: &Map{m: map[Key]string{{name: "key"}: strings.Repeat("a", 255)}}},
-		{err: "invalid value", seed: &Map{m: map[Key]string{{name: "key"}: "Приве́т"}}},
-		{err: "invalid value", seed: &Map{m: map[Key]string{{name: "key"}: strings.Repeat("a", 256)}}},
+		{err: "", seed: &Map{m: map[Key]tagContent{{name: "key"}: {value: "", m: createMetadatas()}}}},
+		{err: "", seed: &Map{m: map[Key]tagContent{{name: "key"}: {value: strings.Repeat("a", 255), m: createMetadatas()}}}},
+		{err: "invalid value", seed: &Map{m: map[Key]tagContent{{name: "key"}: {value: "Приве́т", m: createMetadatas()}}}},
+		{err: "invalid value", seed: &Map{m: map[Key]tagContent{{name: "key"}: {value: strings.Repeat("a", 256), m: createMetadatas()}}}},
 	}
 
 	for i, tt := range tests {
@@ -216,7 +216,65 @@ func makeTestTagMap(ids ...int) *Map {
 	m := newMap()
 	for _, v := range ids {
 		k, _ := NewKey(fmt.Sprintf("k%d", v))
-		m.m[k] = fmt.Sprintf("v%d", v)
+		m.m[k] = tagContent{value: fmt.Sprintf("v%d", v), m: createMetadatas()}
 	}
 	return m
 }
+
+func TestMetadata(t *testing.T) {
+	k1, _ := NewKey("k1")
+	k2, _ := NewKey("k2")
+	k3, _ := NewKey("k3")
+	ctx, err := New(context.Background(),
+		Insert(k1, "v1"),
+		Insert(k2, "v2", WithTTL(TTLNoPropagation)),
+		Upsert(k3, "v3", WithTTL(TTLNoPropagation), WithTTL(TTLUnlimitedPropagation)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := FromContext(ctx)
+	want := map[Key]TTL{
+		k1: TTLUnlimitedPropagation,
+		k2: TTLNoPropagation,
+		k3: TTLUnlimitedPropagation,
+	}
+	for k, ttl := range want {
+		if got := m.m[k].m.ttl; got != ttl {
+			t.Errorf("%s: ttl = %v; want %v", k.Name(), got, ttl)
+		}
+	}
+
+	// Metadata is kept when deriving new maps and replaced on update.
+	ctx, _ = New(ctx, Update(k1, "v1", WithTTL(TTLNoPropagation)))
+	m = FromContext(ctx)
+	if got := m.m[k2].m.ttl; got != TTLNoPropagation {
+		t.Errorf("%s: ttl = %v; want %v", k2.Name(), got, TTLNoPropagation)
+	}
+	if got := m.m[k1].m.ttl; got != TTLNoPropagation {
+		t.Errorf("%s: ttl = %v; want %v", k1.Name(), got, TTLNoPropagation)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	k1, _ := NewKey("k1")
+	k2, _ := NewKey("k2")
+	k3, _ := NewKey("k3")
+	octx, _ := New(context.Background(),
+		Insert(k2, "other"),
+		Insert(k3, "v3", WithTTL(TTLNoPropagation)))
+	other := FromContext(octx)
+
+	ctx, _ := New(context.Background(), Insert(k1, "v1"), Insert(k2, "v2"))
+	ctx, err := New(ctx, Merge(other), Merge(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := newMap()
+	want.insert(k1, "v1", createMetadatas())
+	want.insert(k2, "v2", createMetadatas())
+	want.insert(k3, "v3", createMetadatas(WithTTL(TTLNoPropagation)))
+	if got := FromContext(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map = %v; want %v", got, want)
+	}
+}
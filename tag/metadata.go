@@ -0,0 +1,64 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tag
+
+const (
+	valueTTLNoPropagation        = 0
+	valueTTLUnlimitedPropagation = -1
+)
+
+// TTL is metadata that specifies the number of hops a tag can propagate.
+// Details about TTL metadata are specified at
+// https://github.com/census-instrumentation/opencensus-specs/blob/master/tags/TagMap.md#tagmetadata
+type TTL struct {
+	ttl int
+}
+
+var (
+	// TTLUnlimitedPropagation is TTL metadata that allows a tag to propagate
+	// without any limit on the number of hops. It is the default.
+	TTLUnlimitedPropagation = TTL{ttl: valueTTLUnlimitedPropagation}
+
+	// TTLNoPropagation is TTL metadata that prevents a tag from propagating
+	// outside of the process, for example tags holding user IDs.
+	TTLNoPropagation = TTL{ttl: valueTTLNoPropagation}
+)
+
+type metadatas struct {
+	ttl TTL
+}
+
+// Metadata applies metadatas specified by the function.
+type Metadata func(*metadatas)
+
+// WithTTL applies metadata with the provided ttl.
+func WithTTL(ttl TTL) Metadata {
+	return func(m *metadatas) {
+		m.ttl = ttl
+	}
+}
+
+func createMetadatas(mds ...Metadata) metadatas {
+	var metas metadatas
+	metas.ttl = TTLUnlimitedPropagation
+	for _, md := range mds {
+		md(&metas)
+	}
+	return metas
+}
+
+func (m metadatas) propagates() bool {
+	return m.ttl.ttl != valueTTLNoPropagation
+}
@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package propagation implements the binary trace context format.
+// Package propagation implements the binary trace context format and
+// defines the interfaces implemented by text-based propagation formats.
 package propagation // import "go.opencensus.io/trace/propagation"
 
 // TODO: link to external spec document.
@@ -32,6 +33,10 @@ package propagation // import "go.opencensus.io/trace/propagation"
 // TraceId: (field_id = 0, len = 16, default = "0000000000000000") - 16-byte array representing the trace_id.
 // SpanId: (field_id = 1, len = 8, default = "00000000") - 8-byte array representing the span_id.
 // TraceOptions: (field_id = 2, len = 1, default = "0") - 1-byte array representing the trace_options.
+// Tracestate: (field_id = 3, len = varint, optional) - the length of the tracestate encoded as an
+// unsigned varint, followed by the tracestate entries in the W3C tracestate header format
+// ("key1=value1,key2=value2"), at most 512 bytes long. Only written by BinaryWithTracestate;
+// decoders ignore it if they do not support it.
 //
 // Fields MUST be encoded using the field id order (smaller to higher).
 //
@@ -46,9 +51,19 @@ package propagation // import "go.opencensus.io/trace/propagation"
 // trace_options = {1};
 
 import (
+	"encoding/binary"
 	"net/http"
+	"strings"
 
 	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/tracestate"
+)
+
+const (
+	tracestateFieldID = 3
+	// maxTracestateLen is the maximum length of the encoded tracestate, the
+	// same as for the W3C tracestate header.
+	maxTracestateLen = 512
 )
 
 // Binary returns the binary format representation of a SpanContext.
@@ -67,10 +82,36 @@ func Binary(sc trace.SpanContext) []byte {
 	return b[:]
 }
 
+// BinaryWithTracestate returns the binary format representation of a
+// SpanContext, including its Tracestate if it has any entries. A Tracestate
+// longer than 512 bytes once encoded is omitted.
+//
+// If sc is the zero value, BinaryWithTracestate returns nil.
+func BinaryWithTracestate(sc trace.SpanContext) []byte {
+	b := Binary(sc)
+	entries := sc.Tracestate.Entries()
+	if b == nil || len(entries) == 0 {
+		return b
+	}
+	pairs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		pairs = append(pairs, e.Key+"="+e.Value)
+	}
+	ts := strings.Join(pairs, ",")
+	if len(ts) > maxTracestateLen {
+		return b
+	}
+	var lb [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lb[:], uint64(len(ts)))
+	b = append(b, tracestateFieldID)
+	b = append(b, lb[:n]...)
+	return append(b, ts...)
+}
+
 // FromBinary returns the SpanContext represented by b.
 //
 // If b has an unsupported version ID or contains no TraceID, FromBinary
-// returns with ok==false.
+// returns with ok==false. A malformed or oversized Tracestate field is ignored.
 func FromBinary(b []byte) (sc trace.SpanContext, ok bool) {
 	if len(b) == 0 || b[0] != 0 {
 		return trace.SpanContext{}, false
@@ -88,10 +129,35 @@ func FromBinary(b []byte) (sc trace.SpanContext, ok bool) {
 	}
 	if len(b) >= 2 && b[0] == 2 {
 		sc.TraceOptions = trace.TraceOptions(b[1])
+		b = b[2:]
+	}
+	if len(b) >= 2 && b[0] == tracestateFieldID {
+		sc.Tracestate = tracestateFromBinary(b[1:])
 	}
 	return sc, true
 }
 
+func tracestateFromBinary(b []byte) *tracestate.Tracestate {
+	l, n := binary.Uvarint(b)
+	if n <= 0 || l > maxTracestateLen || uint64(len(b)-n) < l {
+		return nil
+	}
+	h := string(b[n : n+int(l)])
+	var entries []tracestate.Entry
+	for _, pair := range strings.Split(h, ",") {
+		kv := strings.Split(pair, "=")
+		if len(kv) != 2 {
+			return nil
+		}
+		entries = append(entries, tracestate.Entry{Key: kv[0], Value: kv[1]})
+	}
+	ts, err := tracestate.New(nil, entries...)
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
 // HTTPFormat implementations propagate span contexts
 // in HTTP requests.
 //
@@ -16,10 +16,15 @@ package propagation
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
 	. "go.opencensus.io/trace"
+	"go.opencensus.io/trace/tracestate"
 )
 
 func TestBinary(t *testing.T) {
@@ -148,3 +153,112 @@ func BenchmarkFromBinary(b *testing.B) {
 		fmt.Println(x) // try to prevent optimizing-out
 	}
 }
+
+func TestHTTPHeaderCarrier(t *testing.T) {
+	h := http.Header{}
+	c := HTTPHeaderCarrier(h)
+	c.Set("x-custom-header", "a")
+	if got, want := h.Get("X-Custom-Header"), "a"; got != want {
+		t.Errorf("h.Get() = %q; want %q", got, want)
+	}
+	h.Add("X-Custom-Header", "b")
+	if got, want := c.Get("x-custom-header"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("c.Get() = %v; want %v", got, want)
+	}
+	if got := c.Get("missing"); got != nil {
+		t.Errorf("c.Get(missing) = %v; want nil", got)
+	}
+}
+
+func TestMapCarrier(t *testing.T) {
+	c := MapCarrier{}
+	c.Set("key", "a")
+	c.Set("key", "b")
+	if got, want := c.Get("key"), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("c.Get() = %v; want %v", got, want)
+	}
+	if got := c.Get("Key"); got != nil {
+		t.Errorf("c.Get(Key) = %v; want nil", got)
+	}
+}
+
+type fakeTextMapFormat struct{}
+
+func (fakeTextMapFormat) SpanContextFromCarrier(c TextMapCarrier) (SpanContext, bool) {
+	v := c.Get("fake-trace")
+	if len(v) == 0 {
+		return SpanContext{}, false
+	}
+	return SpanContext{TraceOptions: TraceOptions(len(v[0]))}, true
+}
+
+func (fakeTextMapFormat) SpanContextToCarrier(sc SpanContext, c TextMapCarrier) {
+	c.Set("fake-trace", strings.Repeat("x", int(sc.TraceOptions)))
+}
+
+func TestNewHTTPFormat(t *testing.T) {
+	f := NewHTTPFormat(fakeTextMapFormat{})
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	f.SpanContextToRequest(SpanContext{TraceOptions: 3}, req)
+	if got, want := req.Header.Get("Fake-Trace"), "xxx"; got != want {
+		t.Errorf("header = %q; want %q", got, want)
+	}
+	sc, ok := f.SpanContextFromRequest(req)
+	if !ok || sc.TraceOptions != 3 {
+		t.Errorf("SpanContextFromRequest() = %v, %v; want TraceOptions 3, true", sc, ok)
+	}
+}
+
+func TestBinaryWithTracestate(t *testing.T) {
+	ts, err := tracestate.New(nil, tracestate.Entry{Key: "foo", Value: "bar"}, tracestate.Entry{Key: "hello", Value: "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := SpanContext{
+		TraceID:      TraceID{0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f},
+		SpanID:       SpanID{0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68},
+		TraceOptions: 1,
+		Tracestate:   ts,
+	}
+	b := BinaryWithTracestate(sc)
+	if want := append(Binary(sc), append([]byte{3, 19}, "foo=bar,hello=world"...)...); !bytes.Equal(b, want) {
+		t.Errorf("BinaryWithTracestate: got serialization %02x want %02x", b, want)
+	}
+	got, ok := FromBinary(b)
+	if !ok {
+		t.Fatalf("FromBinary: got ok==%t, want true", ok)
+	}
+	if !reflect.DeepEqual(got, sc) {
+		t.Errorf("FromBinary: got %v want %v", got, sc)
+	}
+
+	sc.Tracestate = nil
+	if b, want := BinaryWithTracestate(sc), Binary(sc); !bytes.Equal(b, want) {
+		t.Errorf("BinaryWithTracestate without tracestate: got serialization %02x want %02x", b, want)
+	}
+
+	// A truncated tracestate field is ignored.
+	got, ok = FromBinary(append(Binary(sc), 3, 19, 'f', 'o'))
+	if !ok || got != sc {
+		t.Errorf("FromBinary with truncated tracestate: got %v, %t want %v, true", got, ok, sc)
+	}
+
+	// Tracestates longer than maxTracestateLen are neither encoded nor decoded.
+	long := strings.Repeat("a", maxTracestateLen/2)
+	sc.Tracestate, err = tracestate.New(nil, tracestate.Entry{Key: "foo", Value: long}, tracestate.Entry{Key: "bar", Value: long})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, want := BinaryWithTracestate(sc), Binary(sc); !bytes.Equal(b, want) {
+		t.Errorf("BinaryWithTracestate with oversized tracestate: got serialization %02x want %02x", b, want)
+	}
+	oversized := "foo=" + long + ",bar=" + long
+	var lb [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lb[:], uint64(len(oversized)))
+	b = append(append(append(Binary(sc), 3), lb[:n]...), oversized...)
+	sc.Tracestate = nil
+	got, ok = FromBinary(b)
+	if !ok || got != sc {
+		t.Errorf("FromBinary with oversized tracestate: got %v, %t want %v, true", got, ok, sc)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019, OpenCensus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"net/http"
+	"net/textproto"
+
+	"go.opencensus.io/trace"
+)
+
+// TextMapCarrier is a set of string key/value pairs that span contexts
+// can be propagated in, such as HTTP headers, gRPC metadata or message
+// queue headers.
+//
+// Get returns all the values associated with key, or nil if there are none.
+//
+// Set replaces any existing values associated with key with value.
+type TextMapCarrier interface {
+	Get(key string) []string
+	Set(key, value string)
+}
+
+// TextMapFormat implementations propagate span contexts in
+// any TextMapCarrier.
+//
+// SpanContextFromCarrier extracts a span context from the carrier.
+//
+// SpanContextToCarrier modifies the given carrier to include the given
+// span context.
+type TextMapFormat interface {
+	SpanContextFromCarrier(c TextMapCarrier) (sc trace.SpanContext, ok bool)
+	SpanContextToCarrier(sc trace.SpanContext, c TextMapCarrier)
+}
+
+// HTTPHeaderCarrier adapts http.Header to the TextMapCarrier interface.
+// Keys are canonicalized as by http.CanonicalHeaderKey.
+type HTTPHeaderCarrier http.Header
+
+var _ TextMapCarrier = HTTPHeaderCarrier(nil)
+
+// Get returns the values associated with the canonicalized key.
+func (h HTTPHeaderCarrier) Get(key string) []string {
+	return h[textproto.CanonicalMIMEHeaderKey(key)]
+}
+
+// Set sets the header entry associated with key to the single value.
+func (h HTTPHeaderCarrier) Set(key, value string) {
+	http.Header(h).Set(key, value)
+}
+
+// MapCarrier adapts a map[string]string to the TextMapCarrier interface.
+// Keys are case sensitive.
+type MapCarrier map[string]string
+
+var _ TextMapCarrier = MapCarrier(nil)
+
+// Get returns the value associated with key as a single element slice,
+// or nil if key is not present.
+func (m MapCarrier) Get(key string) []string {
+	v, ok := m[key]
+	if !ok {
+		return nil
+	}
+	return []string{v}
+}
+
+// Set sets the value associated with key.
+func (m MapCarrier) Set(key, value string) {
+	m[key] = value
+}
+
+// NewHTTPFormat returns an HTTPFormat that propagates span contexts in
+// HTTP request headers using f.
+func NewHTTPFormat(f TextMapFormat) HTTPFormat {
+	return &textMapHTTPFormat{f: f}
+}
+
+type textMapHTTPFormat struct {
+	f TextMapFormat
+}
+
+func (t *textMapHTTPFormat) SpanContextFromRequest(req *http.Request) (sc trace.SpanContext, ok bool) {
+	return t.f.SpanContextFromCarrier(HTTPHeaderCarrier(req.Header))
+}
+
+func (t *textMapHTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
+	t.f.SpanContextToCarrier(sc, HTTPHeaderCarrier(req.Header))
+}
@@ -16,6 +16,8 @@ package trace
 
 import (
 	"encoding/binary"
+
+	"go.opencensus.io/trace/tracestate"
 )
 
 const defaultSamplingProbability = 1e-4
@@ -30,11 +32,22 @@ type SamplingParameters struct {
 	SpanID          SpanID
 	Name            string
 	HasRemoteParent bool
+
+	// Tracestate is the tracestate of the new span: the parent's tracestate
+	// (ParentContext.Tracestate) with the modifications requested in the
+	// StartOptions applied.
+	Tracestate *tracestate.Tracestate
 }
 
 // SamplingDecision is the value returned by a Sampler.
 type SamplingDecision struct {
 	Sample bool
+
+	// TracestateEntries and TracestateDeletes modify the tracestate of
+	// the new span in the same way as the fields of the same names in
+	// StartOptions.
+	TracestateEntries []tracestate.Entry
+	TracestateDeletes []string
 }
 
 // ProbabilitySampler returns a Sampler that samples a given fraction of traces.
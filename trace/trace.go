@@ -141,6 +141,17 @@ type StartOptions struct {
 	// SpanKind represents the kind of a span. If none is set,
 	// SpanKindUnspecified is used.
 	SpanKind int
+
+	// TracestateEntries are inserted into the tracestate inherited from the
+	// parent, replacing existing entries with the same keys, to form the
+	// tracestate of the new span and of its children.
+	TracestateEntries []tracestate.Entry
+
+	// TracestateDeletes are the keys of the entries removed from the
+	// tracestate inherited from the parent. They are removed before
+	// TracestateEntries are inserted, and are removed even if some of
+	// TracestateEntries are invalid and therefore not inserted.
+	TracestateDeletes []string
 }
 
 // StartOption apply changes to StartOptions.
@@ -161,6 +172,24 @@ func WithSampler(sampler Sampler) StartOption {
 	}
 }
 
+// WithTracestateEntries makes new spans be created with the given entries
+// inserted into the tracestate inherited from the parent.
+// If any of the entries is invalid, none of them are inserted; keys removed
+// with WithoutTracestateKeys are still removed.
+func WithTracestateEntries(entries ...tracestate.Entry) StartOption {
+	return func(o *StartOptions) {
+		o.TracestateEntries = append(o.TracestateEntries, entries...)
+	}
+}
+
+// WithoutTracestateKeys makes new spans be created with the entries with the
+// given keys removed from the tracestate inherited from the parent.
+func WithoutTracestateKeys(keys ...string) StartOption {
+	return func(o *StartOptions) {
+		o.TracestateDeletes = append(o.TracestateDeletes, keys...)
+	}
+}
+
 // StartSpan starts a new child span of the current span in the context. If
 // there is no span in the context, creates a new trace and span.
 //
@@ -204,6 +233,7 @@ func StartSpanWithRemoteParent(ctx context.Context, name string, parent SpanCont
 func startSpanInternal(name string, hasParent bool, parent SpanContext, remoteParent bool, o StartOptions) *Span {
 	span := &Span{}
 	span.spanContext = parent
+	span.spanContext.Tracestate = modifyTracestate(parent.Tracestate, o.TracestateEntries, o.TracestateDeletes)
 
 	cfg := config.Load().(*Config)
 
@@ -222,12 +252,15 @@ func startSpanInternal(name string, hasParent bool, parent SpanContext, remotePa
 		if o.Sampler != nil {
 			sampler = o.Sampler
 		}
-		span.spanContext.setIsSampled(sampler(SamplingParameters{
+		decision := sampler(SamplingParameters{
 			ParentContext:   parent,
 			TraceID:         span.spanContext.TraceID,
 			SpanID:          span.spanContext.SpanID,
 			Name:            name,
-			HasRemoteParent: remoteParent}).Sample)
+			HasRemoteParent: remoteParent,
+			Tracestate:      span.spanContext.Tracestate})
+		span.spanContext.setIsSampled(decision.Sample)
+		span.spanContext.Tracestate = modifyTracestate(span.spanContext.Tracestate, decision.TracestateEntries, decision.TracestateDeletes)
 	}
 
 	if !internal.LocalSpanStoreEnabled && !span.spanContext.IsSampled() {
@@ -261,6 +294,25 @@ func startSpanInternal(name string, hasParent bool, parent SpanContext, remotePa
 	return span
 }
 
+// modifyTracestate returns ts with the entries with keys in deletes removed
+// and entries inserted. If the result would be invalid, ts is returned.
+func modifyTracestate(ts *tracestate.Tracestate, entries []tracestate.Entry, deletes []string) *tracestate.Tracestate {
+	if len(entries) == 0 && len(deletes) == 0 {
+		return ts
+	}
+	if len(deletes) > 0 {
+		ts = tracestate.Delete(ts, deletes...)
+	}
+	if len(entries) == 0 {
+		return ts
+	}
+	modified, err := tracestate.New(ts, entries...)
+	if err != nil {
+		return ts
+	}
+	return modified
+}
+
 // End ends the span.
 func (s *Span) End() {
 	if s == nil {
@@ -230,6 +230,78 @@ func TestStartSpanWithRemoteParent(t *testing.T) {
 	}
 }
 
+func TestStartSpanWithTracestate(t *testing.T) {
+	parent, _ := tracestate.New(nil,
+		tracestate.Entry{Key: "foo", Value: "bar"},
+		tracestate.Entry{Key: "hello", Value: "world"})
+	sc := SpanContext{
+		TraceID:      tid,
+		SpanID:       sid,
+		TraceOptions: 0x1,
+		Tracestate:   parent,
+	}
+	ctx, span := StartSpanWithRemoteParent(context.Background(), "parent", sc,
+		WithTracestateEntries(tracestate.Entry{Key: "vendor", Value: "1"}),
+		WithoutTracestateKeys("hello"))
+	want := []tracestate.Entry{{Key: "vendor", Value: "1"}, {Key: "foo", Value: "bar"}}
+	if got := span.SpanContext().Tracestate.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("span tracestate = %v; want %v", got, want)
+	}
+	if got, want := parent.Entries(), []tracestate.Entry{{Key: "foo", Value: "bar"}, {Key: "hello", Value: "world"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parent tracestate was modified: %v; want %v", got, want)
+	}
+
+	_, child := StartSpan(ctx, "child")
+	if got := child.SpanContext().Tracestate.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("child tracestate = %v; want %v", got, want)
+	}
+
+	// Invalid entries leave the tracestate unchanged.
+	_, child = StartSpan(ctx, "child", WithTracestateEntries(tracestate.Entry{Key: "Invalid", Value: "1"}))
+	if got := child.SpanContext().Tracestate.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("child tracestate = %v; want %v", got, want)
+	}
+
+	// Deletes are applied even if the entries are invalid.
+	_, child = StartSpan(ctx, "child",
+		WithTracestateEntries(tracestate.Entry{Key: "Invalid", Value: "1"}),
+		WithoutTracestateKeys("vendor"))
+	want = []tracestate.Entry{{Key: "foo", Value: "bar"}}
+	if got := child.SpanContext().Tracestate.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("child tracestate = %v; want %v", got, want)
+	}
+}
+
+func TestSamplerTracestate(t *testing.T) {
+	parent, _ := tracestate.New(nil, tracestate.Entry{Key: "priority", Value: "1"})
+	sc := SpanContext{
+		TraceID:    tid,
+		SpanID:     sid,
+		Tracestate: parent,
+	}
+	var params SamplingParameters
+	sampler := func(p SamplingParameters) SamplingDecision {
+		params = p
+		v, _ := p.Tracestate.Get("priority")
+		return SamplingDecision{
+			Sample:            v == "1",
+			TracestateEntries: []tracestate.Entry{{Key: "sampled", Value: "true"}},
+			TracestateDeletes: []string{"priority"},
+		}
+	}
+	_, span := StartSpanWithRemoteParent(context.Background(), "span", sc, WithSampler(sampler))
+	if got, want := params.Tracestate, parent; got != want {
+		t.Errorf("SamplingParameters.Tracestate = %v; want %v", got, want)
+	}
+	if !span.SpanContext().IsSampled() {
+		t.Error("span is not sampled; want sampled")
+	}
+	want := []tracestate.Entry{{Key: "sampled", Value: "true"}}
+	if got := span.SpanContext().Tracestate.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("span tracestate = %v; want %v", got, want)
+	}
+}
+
 // startSpan returns a context with a new Span that is recording events and will be exported.
 func startSpan(o StartOptions) *Span {
 	_, span := StartSpanWithRemoteParent(context.Background(), "span0",
@@ -63,6 +63,20 @@ func (ts *Tracestate) Entries() []Entry {
 	return ts.entries
 }
 
+// Get returns the value of the entry with the given key, and whether
+// such an entry exists.
+func (ts *Tracestate) Get(key string) (string, bool) {
+	if ts == nil {
+		return "", false
+	}
+	for _, entry := range ts.entries {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return "", false
+}
+
 func (ts *Tracestate) remove(key string) *Entry {
 	for index, entry := range ts.entries {
 		if entry.Key == key {
@@ -145,3 +159,22 @@ func New(parent *Tracestate, entries ...Entry) (*Tracestate, error) {
 	}
 	return &tracestate, nil
 }
+
+// Delete creates a Tracestate object from a parent with the entries with the given
+// keys removed. The remaining entries keep their order. Keys not present in the
+// parent are ignored.
+//
+// Delete returns nil if no entries remain.
+func Delete(parent *Tracestate, keys ...string) *Tracestate {
+	if parent == nil || len(parent.entries) == 0 {
+		return nil
+	}
+	tracestate := Tracestate{entries: append([]Entry{}, parent.entries...)}
+	for _, key := range keys {
+		tracestate.remove(key)
+	}
+	if len(tracestate.entries) == 0 {
+		return nil
+	}
+	return &tracestate
+}
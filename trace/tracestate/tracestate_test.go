@@ -39,24 +39,12 @@ func checkSize(t *testing.T, tracestate *Tracestate, wantSize int, testname stri
 	}
 }
 
-func (ts *Tracestate) get(key string) (string, bool) {
-	if ts == nil {
-		return "", false
-	}
-	for _, entry := range ts.entries {
-		if entry.Key == key {
-			return entry.Value, true
-		}
-	}
-	return "", false
-}
-
 func checkKeyValue(t *testing.T, tracestate *Tracestate, key, wantValue, testname string) {
 	wantOk := true
 	if wantValue == "" {
 		wantOk = false
 	}
-	gotValue, gotOk := tracestate.get(key)
+	gotValue, gotOk := tracestate.Get(key)
 	if wantOk != gotOk || gotValue != wantValue {
 		t.Errorf("test:%s: get value for key=%s failed: got %q want %q", testname, key, gotValue, wantValue)
 	}
@@ -311,3 +299,26 @@ func TestEntriesWithNil(t *testing.T) {
 		t.Errorf("zero value should have no entries, got %v; want %v", got, want)
 	}
 }
+
+func TestDelete(t *testing.T) {
+	testname := "TestDelete"
+	parent, err := New(nil, Entry{Key: "foo", Value: "1"}, Entry{Key: "bar", Value: "2"}, Entry{Key: "baz", Value: "3"})
+	checkError(t, parent, err, testname, "create failed from a valid entries")
+
+	tracestate := Delete(parent, "bar", "missing")
+	checkSize(t, tracestate, 2, testname)
+	checkFront(t, tracestate, "foo", testname)
+	checkBack(t, tracestate, "baz", testname)
+	checkKeyValue(t, tracestate, "bar", "", testname)
+
+	// The parent must not be modified.
+	checkSize(t, parent, 3, testname)
+	checkKeyValue(t, parent, "bar", "2", testname)
+
+	if got := Delete(parent, "foo", "bar", "baz"); got != nil {
+		t.Errorf("test:%s: deleting all entries: got %v want nil", testname, got)
+	}
+	if got := Delete(nil, "foo"); got != nil {
+		t.Errorf("test:%s: deleting from nil: got %v want nil", testname, got)
+	}
+}